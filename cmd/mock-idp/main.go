@@ -0,0 +1,253 @@
+// Command mock-idp is a minimal OpenID Connect provider for local development and
+// manual testing of SSO login. It issues RS256 ID tokens for whatever identity is
+// typed into its login form and enforces PKCE (S256) on the token endpoint.
+//
+//	go run ./cmd/mock-idp
+//
+// Then configure the API with:
+//
+//	OIDC_PROVIDERS=mock
+//	OIDC_MOCK_ISSUER=http://localhost:9000
+//	OIDC_MOCK_CLIENT_ID=ramah-disabilitas
+//	OIDC_MOCK_REDIRECT_URL=http://localhost:5173/sso/callback
+//	OIDC_MOCK_ROLE_MAP=mahasiswa:student,dosen:lecturer
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "mock-key"
+
+type authRequest struct {
+	ClientID      string
+	RedirectURI   string
+	CodeChallenge string
+	Nonce         string
+	Claims        map[string]interface{}
+	ExpiresAt     time.Time
+}
+
+var (
+	issuer     string
+	signingKey *rsa.PrivateKey
+
+	mu           sync.Mutex
+	codes        = map[string]authRequest{}
+	accessTokens = map[string]map[string]interface{}{}
+)
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html lang="id"><head><meta charset="utf-8"><title>Mock IdP</title></head>
+<body>
+<h1>Mock Identity Provider</h1>
+<form method="post" action="/authorize">
+  {{range $k, $v := .Query}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">{{end}}
+  <p><label>Subject <input name="sub" value="mock-user-1" required></label></p>
+  <p><label>Email <input name="email" type="email" value="mahasiswa@kampus.ac.id" required></label></p>
+  <p><label>Nama <input name="name" value="Mahasiswa Uji"></label></p>
+  <p><label>Role <input name="role" value="mahasiswa"></label></p>
+  <p><label><input type="checkbox" name="email_verified" value="true" checked> Email terverifikasi</label></p>
+  <button type="submit">Login</button>
+</form>
+</body></html>`))
+
+func main() {
+	addr := getenv("MOCK_IDP_ADDR", ":9000")
+	issuer = getenv("MOCK_IDP_ISSUER", "http://localhost:9000")
+
+	var err error
+	signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal("Failed to generate signing key:", err)
+	}
+
+	http.HandleFunc("/.well-known/openid-configuration", discovery)
+	http.HandleFunc("/authorize", authorize)
+	http.HandleFunc("/token", token)
+	http.HandleFunc("/userinfo", userinfo)
+	http.HandleFunc("/jwks", jwks)
+
+	log.Printf("Mock IdP listening on %s (issuer %s)\n", addr, issuer)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+func discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"userinfo_endpoint":                     issuer + "/userinfo",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize renders the login form on GET and issues an authorization code on POST.
+func authorize(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		loginPage.Execute(w, map[string]interface{}{"Query": r.URL.Query()})
+		return
+	}
+
+	r.ParseForm()
+	if r.Form.Get("response_type") != "code" || r.Form.Get("code_challenge_method") != "S256" || r.Form.Get("code_challenge") == "" {
+		http.Error(w, "only response_type=code with PKCE S256 is supported", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(r.Form.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	claims := map[string]interface{}{
+		"sub":            r.Form.Get("sub"),
+		"email":          r.Form.Get("email"),
+		"email_verified": r.Form.Get("email_verified") == "true",
+		"name":           r.Form.Get("name"),
+		"role":           r.Form.Get("role"),
+	}
+
+	code := randomString()
+	mu.Lock()
+	codes[code] = authRequest{
+		ClientID:      r.Form.Get("client_id"),
+		RedirectURI:   redirectURI.String(),
+		CodeChallenge: r.Form.Get("code_challenge"),
+		Nonce:         r.Form.Get("nonce"),
+		Claims:        claims,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	mu.Unlock()
+
+	q := redirectURI.Query()
+	q.Set("code", code)
+	q.Set("state", r.Form.Get("state"))
+	redirectURI.RawQuery = q.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	mu.Lock()
+	req, ok := codes[r.Form.Get("code")]
+	delete(codes, r.Form.Get("code"))
+	mu.Unlock()
+
+	if !ok || time.Now().After(req.ExpiresAt) ||
+		r.Form.Get("grant_type") != "authorization_code" ||
+		r.Form.Get("client_id") != req.ClientID ||
+		r.Form.Get("redirect_uri") != req.RedirectURI {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != req.CodeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   issuer,
+		"aud":   req.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": req.Nonce,
+	}
+	for k, v := range req.Claims {
+		claims[k] = v
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(signingKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	accessToken := randomString()
+	mu.Lock()
+	accessTokens[accessToken] = req.Claims
+	mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"id_token":     signed,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+	})
+}
+
+func userinfo(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < 7 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	mu.Lock()
+	claims, ok := accessTokens[auth[7:]]
+	mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, claims)
+}
+
+func jwks(w http.ResponseWriter, r *http.Request) {
+	pub := signingKey.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": keyID,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -26,6 +26,9 @@ func GetLecturerActivities(c *gin.Context) {
 	if limit < 1 {
 		limit = 10
 	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
 
 	resp, err := service.GetLecturerActivities(userID, page, limit)
 	if err != nil {
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetMaterialAnnotations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	annotations, err := service.GetMaterialAnnotations(materialID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Catatan materi berhasil diambil",
+		"data":    annotations,
+	})
+}
+
+func CreateAnnotation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	var input service.AnnotationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	annotation, err := service.CreateAnnotation(materialID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Catatan berhasil disimpan",
+		"data":    annotation,
+	})
+}
+
+func UpdateAnnotation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID catatan tidak valid"})
+		return
+	}
+
+	var input service.AnnotationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	annotation, err := service.UpdateAnnotation(annotationID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Catatan berhasil diperbarui",
+		"data":    annotation,
+	})
+}
+
+func DeleteAnnotation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID catatan tidak valid"})
+		return
+	}
+
+	if err := service.DeleteAnnotation(annotationID, userID.(uint64)); err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Catatan berhasil dihapus"})
+}
+
+func CreateBookmark(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	var input service.BookmarkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	bookmark, err := service.CreateBookmark(materialID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Bookmark berhasil disimpan",
+		"data":    bookmark,
+	})
+}
+
+func DeleteBookmark(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	bookmarkID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID bookmark tidak valid"})
+		return
+	}
+
+	if err := service.DeleteBookmark(bookmarkID, userID.(uint64)); err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark berhasil dihapus"})
+}
+
+// GetMyBookmarks mendukung query ?course_id= untuk membatasi ke satu kelas.
+func GetMyBookmarks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var courseID uint64
+	if raw := c.Query("course_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+			return
+		}
+		courseID = id
+	}
+
+	bookmarks, err := service.GetMyBookmarks(courseID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar bookmark berhasil diambil",
+		"data":    bookmarks,
+	})
+}
+
+// ExportCourseNotes mengunduh catatan, sorotan dan bookmark user di satu kelas dalam format
+// Markdown (default) atau JSON (?format=json).
+func ExportCourseNotes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	export, err := service.ExportCourseNotes(courseID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("catatan-kelas-%d-%s", courseID, time.Now().Format("20060102"))
+
+	if c.DefaultQuery("format", "md") == "json" {
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, filename))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", service.CourseNotesMarkdown(export))
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateAnnouncement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.AnnouncementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	announcement, err := service.CreateAnnouncement(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Pengumuman berhasil dibuat",
+		"data":    announcement,
+	})
+}
+
+func UpdateAnnouncement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	announcementID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID pengumuman tidak valid"})
+		return
+	}
+
+	var input service.AnnouncementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	announcement, err := service.UpdateAnnouncement(announcementID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pengumuman berhasil diperbarui",
+		"data":    announcement,
+	})
+}
+
+func DeleteAnnouncement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	announcementID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID pengumuman tidak valid"})
+		return
+	}
+
+	if err := service.DeleteAnnouncement(announcementID, userID.(uint64)); err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pengumuman berhasil dihapus"})
+}
+
+func GetLecturerCourseAnnouncements(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	announcements, err := service.GetCourseAnnouncementsForStaff(courseID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar pengumuman berhasil diambil",
+		"data":    announcements,
+	})
+}
+
+func GetStudentCourseAnnouncements(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var announcements []model.Announcement
+	if _, preview := previewCourseID(c); preview {
+		announcements, err = service.PreviewCourseAnnouncements(courseID, previewCategories(c))
+	} else {
+		announcements, err = service.GetCourseAnnouncementsForStudent(courseID, userID.(uint64))
+	}
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar pengumuman berhasil diambil",
+		"data":    announcements,
+	})
+}
+
+func MarkAnnouncementRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	announcementID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID pengumuman tidak valid"})
+		return
+	}
+
+	if err := service.MarkAnnouncementRead(announcementID, userID.(uint64)); err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pengumuman ditandai sudah dibaca"})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"ramah-disabilitas-be/internal/model"
 	"ramah-disabilitas-be/internal/service"
 	"ramah-disabilitas-be/pkg/utils"
 	"strconv"
@@ -127,7 +128,12 @@ func GetStudentCourseAssignments(c *gin.Context) {
 		return
 	}
 
-	assignments, err := service.GetStudentAssignmentsByCourse(courseID, userID.(uint64))
+	var assignments []model.Assignment
+	if _, preview := previewCourseID(c); preview {
+		assignments, err = service.PreviewCourseAssignments(courseID)
+	} else {
+		assignments, err = service.GetStudentAssignmentsByCourse(courseID, userID.(uint64))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "unauthorized: anda belum bergabung di kelas ini" {
@@ -157,7 +163,12 @@ func GetAssignmentDetail(c *gin.Context) {
 		return
 	}
 
-	assignment, err := service.GetAssignmentDetail(assignmentID, userID.(uint64))
+	var assignment *model.Assignment
+	if _, preview := previewCourseID(c); preview {
+		assignment, err = service.PreviewAssignmentDetail(assignmentID)
+	} else {
+		assignment, err = service.GetAssignmentDetail(assignmentID, userID.(uint64))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"ramah-disabilitas-be/internal/model"
 	"ramah-disabilitas-be/internal/service"
 	"ramah-disabilitas-be/pkg/utils"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -52,7 +55,13 @@ func VerifyEmail(c *gin.Context) {
 	}
 
 	if err := service.VerifyEmail(token); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		code := "VERIFICATION_TOKEN_INVALID"
+		if strings.Contains(err.Error(), "kadaluarsa") {
+			code = "VERIFICATION_TOKEN_EXPIRED"
+		} else if strings.Contains(err.Error(), "sudah terverifikasi") {
+			code = "EMAIL_ALREADY_VERIFIED"
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": code})
 		return
 	}
 
@@ -61,6 +70,27 @@ func VerifyEmail(c *gin.Context) {
 	})
 }
 
+func ResendVerification(c *gin.Context) {
+	var input service.ResendVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := service.ResendVerification(input.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Jika email terdaftar dan belum terverifikasi, tautan verifikasi baru telah dikirim.",
+	})
+}
+
 func Login(c *gin.Context) {
 	var input service.LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -72,17 +102,80 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := service.Login(input)
+	user, tokens, err := service.Login(input, sessionMeta(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if twoFactorChallengeResponse(c, err) {
+			return
+		} else if strings.Contains(err.Error(), "belum diverifikasi") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "EMAIL_NOT_VERIFIED"})
+		} else if strings.Contains(err.Error(), "dinonaktifkan") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_DEACTIVATED"})
+		} else if strings.Contains(err.Error(), "terkunci") {
+			c.JSON(http.StatusLocked, gin.H{"error": err.Error(), "code": "ACCOUNT_LOCKED"})
+		} else if strings.Contains(err.Error(), "terlalu banyak") {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "code": "TOO_MANY_ATTEMPTS"})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
+	loginResponse(c, user, tokens)
+}
+
+func LoginTwoFactor(c *gin.Context) {
+	var input service.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	user, tokens, err := service.VerifyTwoFactorLogin(input, sessionMeta(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "dinonaktifkan") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_DEACTIVATED"})
+		} else if strings.Contains(err.Error(), "terkunci") {
+			c.JSON(http.StatusLocked, gin.H{"error": err.Error(), "code": "ACCOUNT_LOCKED"})
+		} else if strings.Contains(err.Error(), "kode 2FA") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "TWO_FACTOR_INVALID"})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	loginResponse(c, user, tokens)
+}
+
+// twoFactorChallengeResponse menjawab login yang masih butuh langkah kedua.
+// Mengembalikan false jika err bukan TwoFactorRequiredError.
+func twoFactorChallengeResponse(c *gin.Context, err error) bool {
+	var twoFactorErr *service.TwoFactorRequiredError
+	if !errors.As(err, &twoFactorErr) {
+		return false
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "Masukkan kode dari aplikasi authenticator atau kode cadangan anda",
+		"two_factor_required": true,
+		"challenge_token":     twoFactorErr.ChallengeToken,
+		"expires_in":          twoFactorErr.ExpiresIn,
+	})
+	return true
+}
+
+func loginResponse(c *gin.Context, user *model.User, tokens *service.AuthTokens) {
 	hasFilledAccessibility := user.Accessibility != nil
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login berhasil",
-		"token":   token,
+		"message":       "Login berhasil",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 		"user": gin.H{
 			"id":                       user.ID,
 			"name":                     user.Name,
@@ -91,6 +184,8 @@ func Login(c *gin.Context) {
 			"avatar":                   user.Avatar,
 			"accessibility":            user.Accessibility,
 			"has_filled_accessibility": hasFilledAccessibility,
+			"must_change_password":     user.MustChangePassword,
+			"two_factor_enabled":       user.TwoFactorEnabled,
 		},
 	})
 }
@@ -102,6 +197,15 @@ func GetMe(c *gin.Context) {
 		return
 	}
 
+	// Dalam mode pratinjau, "user" adalah mahasiswa sintetis dengan profil aksesibilitas pilihan dosen
+	if _, preview := previewCourseID(c); preview {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "User detail",
+			"data":    service.GetPreviewStudent(previewCategories(c)),
+		})
+		return
+	}
+
 	user, err := service.GetMe(userID.(uint64))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -121,8 +225,136 @@ func GetMe(c *gin.Context) {
 	})
 }
 
+func ForgotPassword(c *gin.Context) {
+	var input service.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := service.ForgotPassword(input.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Jika email terdaftar, tautan reset password telah dikirim. Silahkan cek email anda.",
+	})
+}
+
+func ResetPassword(c *gin.Context) {
+	var input service.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := service.ResetPassword(input); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "token reset password") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password berhasil diubah. Silahkan login dengan password baru.",
+	})
+}
+
+func RefreshToken(c *gin.Context) {
+	var input service.RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tokens, err := service.RefreshSession(input.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Token berhasil diperbarui",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+func ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input service.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tokens, err := service.ChangePassword(userID.(uint64), input, sessionMeta(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "password") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Password berhasil diubah. Sesi di perangkat lain telah diakhiri.",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
 func Logout(c *gin.Context) {
+	sessionID, exists := c.Get("sessionID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := service.Logout(sessionID.(uint64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logout berhasil",
 	})
 }
+
+func sessionMeta(c *gin.Context) service.SessionMeta {
+	return service.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
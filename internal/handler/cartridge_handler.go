@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"ramah-disabilitas-be/internal/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas ukuran paket Common Cartridge/SCORM yang diunggah.
+const maxCartridgeUploadBytes = 500 << 20
+
+func ExportCourseCartridge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	data, filename, err := service.ExportCourseCartridge(courseID, userID.(uint64))
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// ImportCourseCartridge membuat kelas draft baru dari paket IMS Common Cartridge (.imscc) atau
+// SCORM 1.2 (.zip) yang diunggah lewat form-data key "file".
+func ImportCourseCartridge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File paket wajib diunggah (key: 'file')"})
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".imscc" && ext != ".zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format file harus Common Cartridge (.imscc) atau SCORM (.zip)"})
+		return
+	}
+	if file.Size > maxCartridgeUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ukuran paket melebihi batas 500MB"})
+		return
+	}
+
+	tempPath := filepath.Join(os.TempDir(), fmt.Sprintf("import_cartridge_%d%s", time.Now().UnixNano(), ext))
+	if err := c.SaveUploadedFile(file, tempPath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan file sementara"})
+		return
+	}
+	defer os.Remove(tempPath)
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+
+	result, err := service.ImportCourseCartridge(tempPath, baseURL, userID.(uint64))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "tidak valid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Kelas berhasil diimpor",
+		"data":    result,
+	})
+}
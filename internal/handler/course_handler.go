@@ -99,7 +99,11 @@ func CreateCourse(c *gin.Context) {
 
 	course, err := service.CreateCourse(input, userID.(uint64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if strings.Contains(err.Error(), "tidak valid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -133,7 +137,7 @@ func GetMyCourses(c *gin.Context) {
 }
 
 func GetCourseDetail(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -146,18 +150,13 @@ func GetCourseDetail(c *gin.Context) {
 		return
 	}
 
+	// Akses pemilik kelas (atau admin) sudah diperiksa oleh RequirePermission(course:teach)
 	course, err := service.GetCourseDetail(courseID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Kelas tidak ditemukan"})
 		return
 	}
 
-	// Pastikan yang akses adalah pemilik kelas (untuk endpoint lecturer)
-	if course.TeacherID != userID.(uint64) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki akses ke kelas ini"})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Detail kelas berhasil diambil",
 		"data":    course,
@@ -275,8 +274,12 @@ func UpdateCourse(c *gin.Context) {
 
 	course, err := service.UpdateCourse(courseID, input, userID.(uint64))
 	if err != nil {
-		if err.Error() == "unauthorized: you do not own this course" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "tidak valid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "diubah oleh pengguna lain") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -289,6 +292,49 @@ func UpdateCourse(c *gin.Context) {
 	})
 }
 
+func ChangeCourseStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.CourseStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	course, err := service.ChangeCourseStatus(courseID, input, userID.(uint64), sessionMeta(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "tidak valid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Status kelas berhasil diperbarui",
+		"data":    course,
+	})
+}
+
 func DeleteCourse(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -303,9 +349,9 @@ func DeleteCourse(c *gin.Context) {
 		return
 	}
 
-	err = service.DeleteCourse(courseID, userID.(uint64))
+	err = service.DeleteCourse(courseID, userID.(uint64), sessionMeta(c))
 	if err != nil {
-		if err.Error() == "unauthorized: you do not own this course" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		} else if strings.Contains(strings.ToLower(err.Error()), "foreign key constraint fails") || strings.Contains(strings.ToLower(err.Error()), "constraint") {
 			c.JSON(http.StatusConflict, gin.H{"error": "Kelas tidak dapat dihapus karena masih digunakan (terdapat siswa/modul di dalamnya)."})
@@ -340,18 +386,36 @@ func JoinCourse(c *gin.Context) {
 		return
 	}
 
-	err := service.JoinCourse(input.ClassCode, userID.(uint64))
+	result, err := service.JoinCourse(input.ClassCode, userID.(uint64))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "kelas tidak ditemukan" || err.Error() == "anda sudah bergabung di kelas ini" || err.Error() == "anda adalah pengajar di kelas ini" {
 			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "kadaluarsa") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "CLASS_CODE_EXPIRED"})
+			return
+		} else if strings.Contains(err.Error(), "sudah penuh") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "COURSE_FULL"})
+			return
+		} else if strings.Contains(err.Error(), "menunggu persetujuan") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "ENROLLMENT_PENDING"})
+			return
+		} else if strings.Contains(err.Error(), "diarsipkan") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "COURSE_ARCHIVED"})
+			return
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
+	message := "Berhasil bergabung ke kelas"
+	if result.Status == service.JoinStatusPending {
+		message = "Permintaan bergabung terkirim. Tunggu persetujuan pengajar."
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Berhasil bergabung ke kelas",
+		"message": message,
+		"data":    result,
 	})
 }
 
@@ -362,7 +426,8 @@ func GetMyJoinedCourses(c *gin.Context) {
 		return
 	}
 
-	courses, err := service.GetStudentCourses(userID.(uint64))
+	// status=archived menampilkan kelas yang sudah diarsipkan (hanya bisa dilihat)
+	courses, err := service.GetStudentCourses(userID.(uint64), c.DefaultQuery("status", "active"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -457,6 +522,12 @@ func CreateMaterial(c *gin.Context) {
 	rawContent := c.PostForm("raw_content")
 	durationMin, _ := strconv.Atoi(c.PostForm("duration_min"))
 	hasCaptions, _ := strconv.ParseBool(c.PostForm("has_captions"))
+	altText := c.PostForm("alt_text")
+	transcript := c.PostForm("transcript")
+	availableFrom, availableUntil, ok := materialScheduleFromForm(c)
+	if !ok {
+		return
+	}
 
 	// Validasi basic
 	if title == "" {
@@ -471,6 +542,15 @@ func CreateMaterial(c *gin.Context) {
 	// *** Handle File Upload (Jika ada file di form untuk menggantikan source_url) ***
 	file, fileErr := c.FormFile("file")
 	if fileErr == nil {
+		if err := service.ValidateMaterialUpload(model.MaterialType(materialType), file.Filename); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  map[string]string{"file": err.Error()},
+			})
+			return
+		}
+
 		ext := filepath.Ext(file.Filename)
 		uploadDir := "storage/public"
 		if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
@@ -494,11 +574,16 @@ func CreateMaterial(c *gin.Context) {
 
 	input := service.MaterialInput{
 		Title:       title,
-		Type:        model.MaterialType(materialType), // "pdf", "youtube", "text"
+		Type:        model.MaterialType(materialType), // "pdf", "youtube", "text", "audio", "image", "slides"
 		SourceURL:   sourceURL,
 		RawContent:  rawContent,
 		DurationMin: durationMin,
 		HasCaptions: hasCaptions,
+		AltText:     altText,
+		Transcript:  transcript,
+
+		AvailableFrom:  availableFrom,
+		AvailableUntil: availableUntil,
 	}
 
 	material, err := service.CreateMaterial(moduleID, input, userID.(uint64))
@@ -508,6 +593,8 @@ func CreateMaterial(c *gin.Context) {
 			status = http.StatusForbidden
 		} else if strings.Contains(err.Error(), "tidak ditemukan") {
 			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "tidak valid") {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -540,10 +627,25 @@ func UpdateMaterial(c *gin.Context) {
 	rawContent := c.PostForm("raw_content")
 	durationMin, _ := strconv.Atoi(c.PostForm("duration_min"))
 	hasCaptions, _ := strconv.ParseBool(c.PostForm("has_captions"))
+	altText := c.PostForm("alt_text")
+	transcript := c.PostForm("transcript")
+	availableFrom, availableUntil, ok := materialScheduleFromForm(c)
+	if !ok {
+		return
+	}
 
 	// *** Handle File Upload ***
 	file, fileErr := c.FormFile("file")
 	if fileErr == nil {
+		if err := service.ValidateMaterialUpload(model.MaterialType(materialType), file.Filename); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  map[string]string{"file": err.Error()},
+			})
+			return
+		}
+
 		ext := filepath.Ext(file.Filename)
 		uploadDir := "storage/public"
 		if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
@@ -572,6 +674,11 @@ func UpdateMaterial(c *gin.Context) {
 		RawContent:  rawContent,
 		DurationMin: durationMin,
 		HasCaptions: hasCaptions,
+		AltText:     altText,
+		Transcript:  transcript,
+
+		AvailableFrom:  availableFrom,
+		AvailableUntil: availableUntil,
 	}
 	// Jika user tidak mengirim type, kita asumsikan 'text' atau tidak update?
 	// Karena logic service.MaterialInput binding required, di sini kita manual.
@@ -601,6 +708,10 @@ func UpdateMaterial(c *gin.Context) {
 			status = http.StatusForbidden
 		} else if strings.Contains(err.Error(), "tidak ditemukan") {
 			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "tidak valid") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "diubah oleh pengguna lain") {
+			status = http.StatusConflict
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -612,6 +723,36 @@ func UpdateMaterial(c *gin.Context) {
 	})
 }
 
+// materialScheduleFromForm membaca available_from/available_until (RFC3339) dari form.
+// Field kosong berarti tanpa batas. ok=false berarti response error sudah dikirim.
+func materialScheduleFromForm(c *gin.Context) (from, until *time.Time, ok bool) {
+	errs := map[string]string{}
+	parse := func(key string) *time.Time {
+		value := c.PostForm(key)
+		if value == "" {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errs[key] = "Format tanggal harus RFC3339, contoh 2025-01-31T08:00:00+07:00."
+			return nil
+		}
+		return &t
+	}
+
+	from = parse("available_from")
+	until = parse("available_until")
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  errs,
+		})
+		return nil, nil, false
+	}
+	return from, until, true
+}
+
 func ToggleMaterialCompletion(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -628,7 +769,13 @@ func ToggleMaterialCompletion(c *gin.Context) {
 
 	completed, err := service.ToggleMaterialCompletion(userID.(uint64), materialID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "unauthorized") {
+			status = http.StatusForbidden
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -657,7 +804,12 @@ func GetStudentCourseDetail(c *gin.Context) {
 		return
 	}
 
-	course, err := service.GetStudentCourseDetail(courseID, userID.(uint64))
+	var course *model.Course
+	if _, preview := previewCourseID(c); preview {
+		course, err = service.PreviewCourseDetail(courseID, previewCategories(c))
+	} else {
+		course, err = service.GetStudentCourseDetail(courseID, userID.(uint64))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
@@ -689,7 +841,12 @@ func GetMaterialDetail(c *gin.Context) {
 		return
 	}
 
-	material, err := service.GetMaterialDetailWithStatus(materialID, userID.(uint64))
+	var material *model.Material
+	if _, preview := previewCourseID(c); preview {
+		material, err = service.PreviewMaterialDetail(materialID)
+	} else {
+		material, err = service.GetMaterialDetailWithStatus(materialID, userID.(uint64))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
@@ -1061,7 +1218,7 @@ func GradeSubmission(c *gin.Context) {
 		return
 	}
 
-	submission, err := service.GradeSubmission(submissionID, input, userID.(uint64))
+	submission, err := service.GradeSubmission(submissionID, input, userID.(uint64), sessionMeta(c))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
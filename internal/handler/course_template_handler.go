@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CloneCourse(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.CloneCourseInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  utils.FormatValidationError(err),
+			})
+			return
+		}
+	}
+
+	course, err := service.CloneCourse(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Kelas berhasil disalin",
+		"data":    course,
+	})
+}
+
+func PublishCourseTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.PublishTemplateInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  utils.FormatValidationError(err),
+			})
+			return
+		}
+	}
+
+	template, err := service.PublishCourseTemplate(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Template kelas berhasil dipublikasikan",
+		"data":    template,
+	})
+}
+
+func GetCourseTemplates(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := service.GetCourseTemplates(userID.(uint64), c.Query("search"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar template kelas berhasil diambil",
+		"data":    templates,
+	})
+}
+
+func GetCourseTemplateDetail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID template tidak valid"})
+		return
+	}
+
+	template, err := service.GetCourseTemplateDetail(templateID, userID.(uint64))
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Detail template kelas berhasil diambil",
+		"data":    template,
+	})
+}
+
+func DeleteCourseTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID template tidak valid"})
+		return
+	}
+
+	if err := service.DeleteCourseTemplate(templateID, userID.(uint64)); err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template kelas berhasil dihapus",
+	})
+}
+
+func InstantiateCourseTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID template tidak valid"})
+		return
+	}
+
+	var input service.InstantiateTemplateInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  utils.FormatValidationError(err),
+			})
+			return
+		}
+	}
+
+	course, err := service.InstantiateCourseTemplate(templateID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Kelas berhasil dibuat dari template",
+		"data":    course,
+	})
+}
+
+func templateErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "unauthorized"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
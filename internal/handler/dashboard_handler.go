@@ -59,6 +59,9 @@ func GetRecentActivities(c *gin.Context) {
 			limit = l
 		}
 	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
 
 	activities, err := service.GetRecentActivities(userID.(uint64), limit)
 	if err != nil {
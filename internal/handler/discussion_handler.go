@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func bindThreadInput(c *gin.Context) (service.ThreadInput, bool) {
+	var input service.ThreadInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return input, false
+	}
+	return input, true
+}
+
+func CreateCourseThread(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	input, ok := bindThreadInput(c)
+	if !ok {
+		return
+	}
+
+	thread, err := service.CreateCourseThread(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Diskusi berhasil dibuat",
+		"data":    thread,
+	})
+}
+
+// GetCourseThreads mendukung query ?material_id= untuk menyaring per materi dan ?scope=general
+// untuk diskusi umum kelas saja.
+func GetCourseThreads(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var materialID *uint64
+	if raw := c.Query("material_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+			return
+		}
+		materialID = &id
+	}
+
+	threads, err := service.GetCourseThreads(courseID, materialID, c.Query("scope") == "general", userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar diskusi berhasil diambil",
+		"data":    threads,
+	})
+}
+
+func CreateMaterialThread(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	input, ok := bindThreadInput(c)
+	if !ok {
+		return
+	}
+
+	thread, err := service.CreateMaterialThread(materialID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Diskusi berhasil dibuat",
+		"data":    thread,
+	})
+}
+
+func GetMaterialThreads(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	threads, err := service.GetMaterialThreads(materialID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar diskusi berhasil diambil",
+		"data":    threads,
+	})
+}
+
+func GetThreadDetail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	threadID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID diskusi tidak valid"})
+		return
+	}
+
+	thread, err := service.GetThreadDetail(threadID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Detail diskusi berhasil diambil",
+		"data":    thread,
+	})
+}
+
+func CreateThreadReply(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	threadID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID diskusi tidak valid"})
+		return
+	}
+
+	var input service.ReplyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	reply, err := service.CreateReply(threadID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Balasan berhasil dikirim",
+		"data":    reply,
+	})
+}
+
+// moderateThread menangani endpoint kunci/sembunyikan thread yang bentuknya sama.
+func moderateThread(c *gin.Context, fn func(uint64, service.ThreadModerationInput, uint64) (*model.DiscussionThread, error), message string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	threadID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID diskusi tidak valid"})
+		return
+	}
+
+	var input service.ThreadModerationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	thread, err := fn(threadID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    thread,
+	})
+}
+
+func LockThread(c *gin.Context) {
+	moderateThread(c, service.LockThread, "Status kunci diskusi berhasil diperbarui")
+}
+
+func HideThread(c *gin.Context) {
+	moderateThread(c, service.HideThread, "Visibilitas diskusi berhasil diperbarui")
+}
+
+func HideThreadReply(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	replyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID balasan tidak valid"})
+		return
+	}
+
+	var input service.ThreadModerationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	reply, err := service.HideReply(replyID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Visibilitas balasan berhasil diperbarui",
+		"data":    reply,
+	})
+}
+
+func MarkReplyAnswer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	replyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID balasan tidak valid"})
+		return
+	}
+
+	var input service.ThreadModerationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	thread, err := service.MarkReplyAnswer(replyID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Jawaban diskusi berhasil diperbarui",
+		"data":    thread,
+	})
+}
+
+func GetMyMentions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	mentions, err := service.GetMyMentions(userID.(uint64), c.Query("unread") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar mention berhasil diambil",
+		"data":    mentions,
+	})
+}
+
+func MarkMentionsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input service.MentionReadInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := service.MarkMentionsRead(input, userID.(uint64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mention ditandai sudah dibaca"})
+}
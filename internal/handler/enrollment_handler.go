@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func LeaveCourse(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	if err := service.LeaveCourse(courseID, userID.(uint64)); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "unauthorized") {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Berhasil keluar dari kelas",
+	})
+}
+
+func GetMyEnrollmentRequests(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	requests, err := service.GetMyEnrollmentRequests(userID.(uint64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar permintaan bergabung berhasil diambil",
+		"data":    requests,
+	})
+}
+
+func UpdateEnrollmentSettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.EnrollmentSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	course, err := service.UpdateEnrollmentSettings(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(enrollmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pengaturan pendaftaran kelas berhasil diperbarui",
+		"data":    course,
+	})
+}
+
+func RegenerateClassCode(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.RegenerateClassCodeInput
+	// Body opsional: tanpa expires_at, kode baru tidak kadaluarsa
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  utils.FormatValidationError(err),
+			})
+			return
+		}
+	}
+
+	course, err := service.RegenerateClassCode(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(enrollmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kode kelas berhasil diganti. Kode lama tidak berlaku lagi.",
+		"data": gin.H{
+			"class_code":            course.ClassCode,
+			"class_code_expires_at": course.ClassCodeExpiresAt,
+		},
+	})
+}
+
+func GetEnrollmentRequests(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	status := c.DefaultQuery("status", string(model.EnrollmentPending))
+
+	requests, err := service.GetEnrollmentRequests(courseID, status, userID.(uint64))
+	if err != nil {
+		c.JSON(enrollmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar permintaan bergabung berhasil diambil",
+		"data":    requests,
+	})
+}
+
+func ApproveEnrollmentRequest(c *gin.Context) {
+	decideEnrollmentRequest(c, true)
+}
+
+func RejectEnrollmentRequest(c *gin.Context) {
+	decideEnrollmentRequest(c, false)
+}
+
+func decideEnrollmentRequest(c *gin.Context, approve bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("requestId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID permintaan tidak valid"})
+		return
+	}
+
+	var request *model.EnrollmentRequest
+	message := "Permintaan bergabung disetujui"
+	if approve {
+		request, err = service.ApproveEnrollmentRequest(courseID, requestID, userID.(uint64))
+	} else {
+		request, err = service.RejectEnrollmentRequest(courseID, requestID, userID.(uint64))
+		message = "Permintaan bergabung ditolak"
+	}
+	if err != nil {
+		c.JSON(enrollmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    request,
+	})
+}
+
+func enrollmentErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "unauthorized"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "tidak valid"):
+		return http.StatusBadRequest
+	case strings.Contains(err.Error(), "sudah penuh"), strings.Contains(err.Error(), "sudah diproses"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
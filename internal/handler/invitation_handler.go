@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InviteStudentsToCourse(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.InviteStudentsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	results, err := service.InviteStudentsToCourse(courseID, input, userID.(uint64))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Undangan berhasil diproses",
+		"data":    results,
+	})
+}
+
+func GetCourseInvitations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	status := c.DefaultQuery("status", string(model.InvitationPending))
+
+	invitations, err := service.GetCourseInvitations(courseID, status, userID.(uint64))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar undangan berhasil diambil",
+		"data":    invitations,
+	})
+}
+
+func RevokeCourseInvitation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID undangan tidak valid"})
+		return
+	}
+
+	if err := service.RevokeCourseInvitation(courseID, invitationID, userID.(uint64)); err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Undangan berhasil dibatalkan",
+	})
+}
+
+func GetInvitationInfo(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token wajib ada"})
+		return
+	}
+
+	info, err := service.GetInvitationInfo(token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVITATION_INVALID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Detail undangan",
+		"data":    info,
+	})
+}
+
+func AcceptCourseInvitation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input service.AcceptInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	result, err := service.AcceptCourseInvitation(input.Token, userID.(uint64))
+	if err != nil {
+		if strings.Contains(err.Error(), "undangan") && !strings.Contains(err.Error(), "unauthorized") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVITATION_INVALID"})
+			return
+		}
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Undangan diterima. Anda sudah bergabung ke kelas.",
+		"data":    result,
+	})
+}
+
+func RegisterWithInvitation(c *gin.Context) {
+	var input service.RegisterWithInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	user, tokens, err := service.RegisterWithInvitation(input, sessionMeta(c))
+	if err != nil {
+		if twoFactorChallengeResponse(c, err) {
+			return
+		} else if strings.Contains(err.Error(), "undangan") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVITATION_INVALID"})
+		} else if strings.Contains(err.Error(), "email sudah ada") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "EMAIL_EXISTS"})
+		} else {
+			c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	loginResponse(c, user, tokens)
+}
+
+func invitationErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "unauthorized"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "sudah diterima"), strings.Contains(err.Error(), "pengajar di kelas ini"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetMaterialVersions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+
+	versions, err := service.GetMaterialVersions(materialID, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Riwayat versi materi berhasil diambil",
+		"data":    versions,
+	})
+}
+
+func GetMaterialVersion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nomor versi tidak valid"})
+		return
+	}
+
+	materialVersion, err := service.GetMaterialVersion(materialID, version, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Detail versi materi berhasil diambil",
+		"data":    materialVersion,
+	})
+}
+
+// DiffMaterialVersions: ?from=<versi>&to=<versi>, to kosong = versi aktif.
+func DiffMaterialVersions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter from wajib berisi nomor versi"})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter to tidak valid"})
+		return
+	}
+
+	diff, err := service.DiffMaterialVersions(materialID, from, to, userID.(uint64))
+	if err != nil {
+		c.JSON(moduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Perbandingan versi materi berhasil dibuat",
+		"data":    diff,
+	})
+}
+
+func RollbackMaterial(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID materi tidak valid"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nomor versi tidak valid"})
+		return
+	}
+
+	material, err := service.RollbackMaterial(materialID, version, userID.(uint64))
+	if err != nil {
+		status := moduleErrorStatus(err)
+		if strings.Contains(err.Error(), "diubah oleh pengguna lain") {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Materi berhasil dikembalikan ke versi sebelumnya",
+		"data":    material,
+	})
+}
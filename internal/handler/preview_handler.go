@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StartStudentPreview(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	sessionID, _ := c.Get("sessionID")
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kelas tidak valid"})
+		return
+	}
+
+	var input service.StudentPreviewInput
+	// Body opsional: tanpa kategori, pratinjau memakai mahasiswa tanpa kebutuhan aksesibilitas khusus
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validasi input gagal.",
+				"errors":  utils.FormatValidationError(err),
+			})
+			return
+		}
+	}
+
+	preview, err := service.StartStudentPreview(courseID, userID.(uint64), sessionID.(uint64), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "unauthorized") {
+			status = http.StatusForbidden
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mode pratinjau dimulai. Gunakan token ini pada endpoint mahasiswa untuk kelas ini.",
+		"data":    preview,
+	})
+}
+
+// previewCourseID mengembalikan ID kelas yang dipratinjau jika request memakai token pratinjau.
+// previewCategories mengembalikan kategori disabilitas yang dipilih dosen untuk mode pratinjau.
+func previewCategories(c *gin.Context) []string {
+	categories, _ := c.Get("previewCategories")
+	categoryList, _ := categories.([]string)
+	return categoryList
+}
+
+func previewCourseID(c *gin.Context) (uint64, bool) {
+	courseID, ok := c.Get("previewCourseID")
+	if !ok {
+		return 0, false
+	}
+	return courseID.(uint64), true
+}
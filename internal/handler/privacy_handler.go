@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportUserData mengunduh data pribadi user dalam format JSON (default) atau ZIP (?format=zip).
+func ExportUserData(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filename := fmt.Sprintf("data-saya-%s", time.Now().Format("20060102"))
+
+	if c.DefaultQuery("format", "json") == "zip" {
+		archive, err := service.ExportUserDataZip(userID.(uint64))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+		c.Data(http.StatusOK, "application/zip", archive)
+		return
+	}
+
+	export, err := service.ExportUserData(userID.(uint64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+func RequestAccountDeletion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input service.AccountDeletionInput
+	// Body opsional, hanya berisi alasan penghapusan
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := service.RequestAccountDeletion(userID.(uint64), input); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "kelas") || strings.Contains(err.Error(), "sudah dihapus") {
+			status = http.StatusConflict
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tautan konfirmasi penghapusan akun telah dikirim ke email anda",
+	})
+}
+
+func CancelAccountDeletion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := service.CancelAccountDeletion(userID.(uint64)); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Permintaan hapus akun dibatalkan",
+	})
+}
+
+func ConfirmAccountDeletion(c *gin.Context) {
+	var input service.ConfirmAccountDeletionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := service.ConfirmAccountDeletion(input); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "token hapus akun") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "kelas") || strings.Contains(err.Error(), "sudah dihapus") {
+			status = http.StatusConflict
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Akun anda telah dihapus. Terima kasih telah menggunakan layanan kami.",
+	})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/service"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ListSSOProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Daftar penyedia SSO",
+		"data":    service.ListSSOProviders(),
+	})
+}
+
+func StartSSOLogin(c *gin.Context) {
+	authURL, err := service.StartSSOLogin(c.Param("provider"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "tidak ditemukan") {
+			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "tidak dapat dihubungi") {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Arahkan browser ke authorization_url",
+		"authorization_url": authURL,
+	})
+}
+
+// SSOCallback dipanggil frontend dengan code & state yang diterima dari penyedia SSO.
+func SSOCallback(c *gin.Context) {
+	var input service.SSOCallbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validasi input gagal.",
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	user, tokens, err := service.CompleteSSOLogin(c.Param("provider"), input, sessionMeta(c))
+	if err != nil {
+		if twoFactorChallengeResponse(c, err) {
+			return
+		} else if strings.Contains(err.Error(), "tidak ditemukan") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "dinonaktifkan") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_DEACTIVATED"})
+		} else if strings.Contains(err.Error(), "unauthorized") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "SSO_NOT_ALLOWED"})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	loginResponse(c, user, tokens)
+}
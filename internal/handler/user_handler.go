@@ -139,7 +139,7 @@ func UpdateStudentByLecturer(c *gin.Context) {
 		return
 	}
 
-	user, err := service.UpdateStudentByLecturer(studentID, input, userID.(uint64))
+	user, err := service.UpdateStudentByLecturer(studentID, input, userID.(uint64), sessionMeta(c))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
@@ -171,7 +171,7 @@ func DeleteStudentByLecturer(c *gin.Context) {
 		return
 	}
 
-	err = service.DeleteStudentByLecturer(studentID, userID.(uint64))
+	err = service.DeleteStudentByLecturer(studentID, userID.(uint64), sessionMeta(c))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "unauthorized") {
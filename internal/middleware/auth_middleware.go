@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"ramah-disabilitas-be/internal/service"
 	"ramah-disabilitas-be/pkg/utils"
 	"strings"
 
@@ -9,6 +10,33 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var passwordChangeAllowedPaths = map[string]bool{
+	"/api/v1/auth/me":              true,
+	"/api/v1/auth/logout":          true,
+	"/api/v1/auth/change-password": true,
+}
+
+// Ganti password (jika juga diwajibkan) didahulukan sebelum aktivasi 2FA
+var twoFactorSetupAllowedPaths = map[string]bool{
+	"/api/v1/auth/me":              true,
+	"/api/v1/auth/logout":          true,
+	"/api/v1/auth/change-password": true,
+	"/api/v1/auth/2fa":             true,
+	"/api/v1/auth/2fa/setup":       true,
+	"/api/v1/auth/2fa/enable":      true,
+}
+
+// Token pratinjau (dosen sebagai mahasiswa sintetis) hanya boleh membaca konten kelas
+var previewAllowedPaths = map[string]bool{
+	"/api/v1/auth/me":                   true,
+	"/api/v1/courses/:id":               true,
+	"/api/v1/courses/:id/members":       true,
+	"/api/v1/courses/:id/assignments":   true,
+	"/api/v1/courses/:id/announcements": true,
+	"/api/v1/assignments/:id":           true,
+	"/api/v1/materials/:id":             true,
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -39,6 +67,14 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		sessionID, ok := claims["sid"].(float64)
+		if !ok || !service.IsSessionActive(uint64(sessionID)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid atau sudah dicabut"})
+			c.Abort()
+			return
+		}
+		c.Set("sessionID", uint64(sessionID))
+
 		if userID, ok := claims["user_id"].(float64); ok {
 			c.Set("userID", uint64(userID))
 		}
@@ -47,6 +83,48 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Set("role", role)
 		}
 
+		// Akun dengan password bawaan hanya boleh mengganti password sebelum memakai fitur lain
+		if mustChange, _ := claims["pwd_change"].(bool); mustChange && !passwordChangeAllowedPaths[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Anda wajib mengganti password terlebih dahulu",
+				"code":  "PASSWORD_CHANGE_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		// Role yang diwajibkan 2FA harus mengaktifkannya sebelum memakai fitur lain
+		if setupRequired, _ := claims["2fa_setup"].(bool); setupRequired && !twoFactorSetupAllowedPaths[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Anda wajib mengaktifkan verifikasi dua langkah (2FA) terlebih dahulu",
+				"code":  "TWO_FACTOR_SETUP_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		if previewCourseID, ok := claims["preview_course"].(float64); ok {
+			if c.Request.Method != http.MethodGet || !previewAllowedPaths[c.FullPath()] {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": "Mode pratinjau hanya bisa melihat konten kelas",
+					"code":  "PREVIEW_READ_ONLY",
+				})
+				c.Abort()
+				return
+			}
+
+			var categories []string
+			if profile, ok := claims["preview_profile"].([]interface{}); ok {
+				for _, category := range profile {
+					if s, ok := category.(string); ok {
+						categories = append(categories, s)
+					}
+				}
+			}
+			c.Set("previewCourseID", uint64(previewCourseID))
+			c.Set("previewCategories", categories)
+		}
+
 		c.Next()
 	}
 }
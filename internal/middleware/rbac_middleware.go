@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole membatasi route untuk role tertentu. Harus dipasang setelah AuthMiddleware,
+// yang sudah memvalidasi token dan menyimpan "role" di context.
+func RequireRole(roles ...model.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if role == string(r) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak: role anda tidak diizinkan"})
+		c.Abort()
+	}
+}
+
+// AdminMiddleware hanya mengizinkan admin.
+func AdminMiddleware() gin.HandlerFunc {
+	return RequireRole(model.RoleAdmin)
+}
+
+// LecturerMiddleware mengizinkan dosen dan admin.
+func LecturerMiddleware() gin.HandlerFunc {
+	return RequireRole(model.RoleLecturer, model.RoleAdmin)
+}
+
+// RequirePermission memeriksa permission terhadap resource yang ID-nya diambil dari
+// parameter URL param (mis. "id"), memakai policy resolver di service.Authorize.
+func RequirePermission(perm service.Permission, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resourceID, err := strconv.ParseUint(c.Param(param), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			c.Abort()
+			return
+		}
+
+		if previewCourseID, ok := c.Get("previewCourseID"); ok {
+			err = service.AuthorizePreview(c.GetUint64("userID"), previewCourseID.(uint64), perm, resourceID)
+		} else {
+			err = service.Authorize(c.GetUint64("userID"), c.GetString("role"), perm, resourceID)
+		}
+		if err != nil {
+			status := http.StatusForbidden
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
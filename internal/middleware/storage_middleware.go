@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageMiddleware mengamankan file unggahan dan hasil impor paket kursus yang disajikan dari
+// origin API. Halaman HTML (mis. SCO SCORM) dibuka dalam sandbox CSP sehingga script di dalamnya
+// tidak berjalan dengan origin API dan tidak bisa membaca cookie, storage, atau memanggil API
+// atas nama user. File script tidak pernah ditampilkan langsung, hanya diunduh.
+func StorageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Content-Security-Policy", "sandbox")
+		c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+
+		switch strings.ToLower(path.Ext(c.Request.URL.Path)) {
+		case ".js", ".mjs":
+			c.Writer.Header().Set("Content-Disposition", "attachment")
+		}
+
+		c.Next()
+	}
+}
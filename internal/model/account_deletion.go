@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// AccountDeletionRequest mencatat permintaan hapus akun. Baris ini tetap disimpan
+// setelah akun dianonimkan sebagai jejak kapan penghapusan dilakukan.
+type AccountDeletionRequest struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      uint64     `gorm:"index" json:"user_id"`
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	Reason      string     `gorm:"type:text" json:"reason"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
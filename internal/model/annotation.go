@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type AnnotationKind string
+
+const (
+	AnnotationNote      AnnotationKind = "note"      // Catatan bebas, boleh tanpa posisi
+	AnnotationHighlight AnnotationKind = "highlight" // Sorotan pada rentang isi materi
+)
+
+// AnnotationAnchor menunjuk posisi di dalam materi. Field yang dipakai tergantung jenis materi:
+//   - pdf / slides: Page (mulai 1), opsional StartOffset/EndOffset di dalam teks halaman
+//   - text: StartOffset/EndOffset (indeks karakter pada isi materi)
+//   - youtube / audio: StartSec, opsional EndSec
+type AnnotationAnchor struct {
+	Page        *int `json:"page,omitempty"`
+	StartOffset *int `json:"start_offset,omitempty"`
+	EndOffset   *int `json:"end_offset,omitempty"`
+	StartSec    *int `json:"start_sec,omitempty"`
+	EndSec      *int `json:"end_sec,omitempty"`
+}
+
+// MaterialAnnotation adalah catatan atau sorotan pribadi user pada sebuah materi.
+type MaterialAnnotation struct {
+	ID         uint64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64           `gorm:"index:idx_annotation_user_material" json:"user_id"`
+	User       *User            `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	MaterialID uint64           `gorm:"index:idx_annotation_user_material" json:"material_id"`
+	Material   *Material        `gorm:"foreignKey:MaterialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Kind       AnnotationKind   `gorm:"type:varchar(20)" json:"kind"`
+	Anchor     AnnotationAnchor `gorm:"embedded;embeddedPrefix:anchor_" json:"anchor"`
+	Quote      string           `gorm:"type:text" json:"quote,omitempty"` // Teks yang disorot
+	Note       string           `gorm:"type:text" json:"note,omitempty"`
+	Color      string           `gorm:"type:varchar(20)" json:"color,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// MaterialBookmark menandai materi (atau posisi di dalamnya) untuk dibuka kembali.
+type MaterialBookmark struct {
+	ID         uint64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64           `gorm:"index:idx_bookmark_user_material" json:"user_id"`
+	User       *User            `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	MaterialID uint64           `gorm:"index:idx_bookmark_user_material" json:"material_id"`
+	Material   *Material        `gorm:"foreignKey:MaterialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"material,omitempty"`
+	Label      string           `gorm:"type:varchar(255)" json:"label"`
+	Anchor     AnnotationAnchor `gorm:"embedded;embeddedPrefix:anchor_" json:"anchor"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Announcement adalah pengumuman pengajar untuk seluruh mahasiswa kelas. Pengumuman baru terlihat
+// mahasiswa setelah PublishAt (dijadwalkan) dan disajikan sesuai AccessibilityProfile penerima.
+type Announcement struct {
+	ID          uint64                   `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID    uint64                   `gorm:"index" json:"course_id"`
+	Course      *Course                  `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	AuthorID    uint64                   `json:"author_id"`
+	Author      *User                    `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Title       string                   `gorm:"type:varchar(255)" json:"title"`
+	Content     string                   `gorm:"type:text" json:"content"` // Rich text (HTML yang sudah disaring)
+	Pinned      bool                     `gorm:"default:false" json:"pinned"`
+	PublishAt   time.Time                `gorm:"index" json:"publish_at"`
+	Attachments []AnnouncementAttachment `gorm:"foreignKey:AnnouncementID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attachments"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+
+	IsPublished bool                  `gorm:"-" json:"is_published"`
+	IsRead      bool                  `gorm:"-" json:"is_read"`            // Hanya untuk mahasiswa
+	Delivery    *AnnouncementDelivery `gorm:"-" json:"delivery,omitempty"` // Hanya untuk mahasiswa
+}
+
+type AnnouncementAttachment struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	AnnouncementID uint64 `gorm:"index" json:"announcement_id"`
+	Name           string `gorm:"type:varchar(255)" json:"name"`
+	URL            string `gorm:"type:text" json:"url"`
+	AltText        string `gorm:"type:text" json:"alt_text"` // Wajib untuk lampiran gambar
+	MimeType       string `gorm:"type:varchar(100)" json:"mime_type"`
+	FileSize       int64  `json:"file_size"`
+}
+
+// AnnouncementRead menandai pengumuman yang sudah dibaca mahasiswa.
+type AnnouncementRead struct {
+	AnnouncementID uint64        `gorm:"primaryKey" json:"announcement_id"`
+	Announcement   *Announcement `gorm:"foreignKey:AnnouncementID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID         uint64        `gorm:"primaryKey" json:"user_id"`
+	User           *User         `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ReadAt         time.Time     `json:"read_at"`
+}
+
+// AnnouncementDelivery adalah bentuk pengumuman yang disesuaikan dengan AccessibilityProfile
+// penerima (dihitung saat dibaca, tidak disimpan).
+type AnnouncementDelivery struct {
+	Format      string `json:"format"`                // rich atau plain
+	PlainText   string `json:"plain_text,omitempty"`  // Tanpa markup, untuk screen reader / mode fokus
+	SpeechText  string `json:"speech_text,omitempty"` // Kalimat utuh siap dibacakan TTS (tuna netra)
+	VisualAlert bool   `json:"visual_alert"`          // Tampilkan notifikasi visual, jangan andalkan bunyi (tuna rungu)
+}
+
+const (
+	AnnouncementFormatRich  = "rich"
+	AnnouncementFormatPlain = "plain"
+)
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type AuditAction string
+
+const (
+	AuditSubmissionGrade    AuditAction = "submission.grade"
+	AuditStudentUpdate      AuditAction = "student.update"
+	AuditStudentDelete      AuditAction = "student.delete"
+	AuditCourseDelete       AuditAction = "course.delete"
+	AuditCourseStatusChange AuditAction = "course.status_change"
+	AuditCourseStaffAdd     AuditAction = "course_staff.add"
+	AuditCourseStaffUpdate  AuditAction = "course_staff.update"
+	AuditCourseStaffRemove  AuditAction = "course_staff.remove"
+	AuditUserRoleChange     AuditAction = "user.role_change"
+	AuditUserVerify         AuditAction = "user.verify"
+	AuditUserDeactivate     AuditAction = "user.deactivate"
+	AuditUserActivate       AuditAction = "user.activate"
+	AuditUserPasswordReset  AuditAction = "user.password_reset"
+	AuditUserUnlock         AuditAction = "user.unlock"
+	AuditUserSessionsRevoke AuditAction = "user.sessions_revoke"
+	AuditUserTwoFactorReset AuditAction = "user.two_factor_reset"
+)
+
+// AuditLog mencatat satu aksi sensitif. Tabel ini append-only: database menolak
+// UPDATE/DELETE pada tabel ini (lihat database.Migrate).
+type AuditLog struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    *uint64        `gorm:"index" json:"actor_id"`
+	Actor      *User          `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	ActorRole  string         `gorm:"type:varchar(20)" json:"actor_role"`
+	Action     AuditAction    `gorm:"type:varchar(64);index" json:"action"`
+	TargetType string         `gorm:"type:varchar(32);index:idx_audit_target" json:"target_type"`
+	TargetID   uint64         `gorm:"index:idx_audit_target" json:"target_id"`
+	CourseID   *uint64        `gorm:"index" json:"course_id"`
+	Before     datatypes.JSON `json:"before"`
+	After      datatypes.JSON `json:"after"`
+	IPAddress  string         `gorm:"type:varchar(64)" json:"ip_address"`
+	UserAgent  string         `gorm:"type:varchar(255)" json:"user_agent"`
+	CreatedAt  time.Time      `gorm:"index" json:"created_at"`
+}
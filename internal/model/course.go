@@ -7,20 +7,60 @@ import (
 )
 
 type Course struct {
-	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	TeacherID   uint64    `json:"teacher_id"`
-	Title       string    `gorm:"type:varchar(255)" json:"title"`
-	Description string    `gorm:"type:text" json:"description"`
-	Thumbnail   string    `gorm:"type:varchar(255)" json:"thumbnail"`
-	ClassCode   string    `gorm:"uniqueIndex;type:varchar(20)" json:"class_code"`
-	Status      string    `gorm:"type:varchar(20);default:'draft'" json:"status"` // published, draft, archived
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-
-	Modules     []Module     `gorm:"foreignKey:CourseID" json:"modules,omitempty"`
-	Assignments []Assignment `gorm:"foreignKey:CourseID" json:"assignments,omitempty"`
-	Students    []User       `gorm:"many2many:course_students;" json:"students,omitempty"`
-	Progress    float64      `gorm:"-" json:"progress"` // 0-100 percentage
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeacherID   uint64     `json:"teacher_id"`
+	Title       string     `gorm:"type:varchar(255)" json:"title"`
+	Description string     `gorm:"type:text" json:"description"`
+	Thumbnail   string     `gorm:"type:varchar(255)" json:"thumbnail"`
+	ClassCode   string     `gorm:"uniqueIndex;type:varchar(20)" json:"class_code"`
+	Status      string     `gorm:"type:varchar(20);default:'draft'" json:"status"` // published, draft, archived
+	ArchivedAt  *time.Time `json:"archived_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	EnrollmentPolicy   EnrollmentPolicy `gorm:"type:varchar(20);default:'open'" json:"enrollment_policy"`
+	MaxStudents        int              `gorm:"default:0" json:"max_students"` // 0 = tanpa batas
+	ClassCodeExpiresAt *time.Time       `json:"class_code_expires_at"`
+
+	Modules       []Module       `gorm:"foreignKey:CourseID" json:"modules,omitempty"`
+	Assignments   []Assignment   `gorm:"foreignKey:CourseID" json:"assignments,omitempty"`
+	Students      []User         `gorm:"many2many:course_students;" json:"students,omitempty"`
+	Staff         []CourseStaff  `gorm:"foreignKey:CourseID" json:"staff,omitempty"`
+	Announcements []Announcement `gorm:"foreignKey:CourseID" json:"announcements,omitempty"`
+	Progress      float64        `gorm:"-" json:"progress"` // 0-100 percentage
+}
+
+// Siklus hidup kelas: draft -> published -> archived. Kelas archived hanya bisa dilihat
+// (lihat service.ensureCourseWritable dan mutatingPermissions).
+const (
+	CourseStatusDraft     = "draft"
+	CourseStatusPublished = "published"
+	CourseStatusArchived  = "archived"
+)
+
+type EnrollmentPolicy string
+
+const (
+	EnrollmentOpen     EnrollmentPolicy = "open"     // Kode kelas langsung membuat mahasiswa bergabung
+	EnrollmentApproval EnrollmentPolicy = "approval" // Kode kelas membuat permintaan yang harus disetujui pengajar
+)
+
+type CourseStaffRole string
+
+const (
+	StaffRoleCoTeacher CourseStaffRole = "co_teacher"
+	StaffRoleTA        CourseStaffRole = "ta" // Teaching assistant: view & grading only
+)
+
+// CourseStaff is an additional teacher of a course. The owner remains Course.TeacherID.
+type CourseStaff struct {
+	ID        uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID  uint64          `gorm:"uniqueIndex:idx_course_staff" json:"course_id"`
+	Course    *Course         `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID    uint64          `gorm:"uniqueIndex:idx_course_staff;index" json:"user_id"`
+	User      *User           `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user,omitempty"`
+	Role      CourseStaffRole `gorm:"type:varchar(20)" json:"role"`
+	CreatedAt time.Time       `json:"created_at"`
 }
 
 type Module struct {
@@ -29,6 +69,15 @@ type Module struct {
 	Title    string `gorm:"type:varchar(255)" json:"title"`
 	Order    int    `json:"order"`
 
+	// Jadwal rilis. Kosong = langsung terlihat / tidak pernah ditutup.
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	// RequiresPrevious mengunci modul sampai semua materi di modul sebelumnya (urut Order) selesai.
+	RequiresPrevious bool `gorm:"default:false" json:"requires_previous"`
+
+	IsLocked   bool   `gorm:"-" json:"is_locked"`
+	LockReason string `gorm:"-" json:"lock_reason,omitempty"`
+
 	Materials []Material `gorm:"foreignKey:ModuleID" json:"materials,omitempty"`
 }
 
@@ -38,13 +87,27 @@ const (
 	TypeYoutube MaterialType = "youtube"
 	TypePDF     MaterialType = "pdf"
 	TypeText    MaterialType = "text"
+	TypeAudio   MaterialType = "audio"  // Podcast / rekaman kuliah, wajib Transcript
+	TypeImage   MaterialType = "image"  // Wajib AltText
+	TypeSlides  MaterialType = "slides" // PDF atau gambar slide, wajib AltText atau Transcript (catatan pembicara)
 )
 
+// MediaMetadata dibaca server dari file materi (lihat utils.ProbeMedia), bukan dari input user.
+type MediaMetadata struct {
+	MimeType    string `gorm:"type:varchar(100)" json:"mime_type,omitempty"`
+	FileSize    int64  `json:"file_size,omitempty"`
+	DurationSec int    `json:"duration_sec,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	PageCount   int    `json:"page_count,omitempty"`
+}
+
 type Material struct {
 	ID       uint64       `gorm:"primaryKey;autoIncrement" json:"id"`
 	ModuleID uint64       `json:"module_id"`
 	Title    string       `gorm:"type:varchar(255)" json:"title"`
 	Type     MaterialType `gorm:"type:varchar(20)" json:"type"`
+	Order    int          `gorm:"default:0" json:"order"` // Urutan di dalam modul
 
 	SourceURL  string `gorm:"type:text" json:"source_url"`
 	RawContent string `gorm:"type:text" json:"raw_content,omitempty"`
@@ -52,7 +115,21 @@ type Material struct {
 	DurationMin int  `json:"duration_min"`
 	HasCaptions bool `json:"has_captions"`
 
-	IsCompleted bool `gorm:"-" json:"is_completed"`
+	AltText    string        `gorm:"type:text" json:"alt_text,omitempty"`
+	Transcript string        `gorm:"type:text" json:"transcript,omitempty"`
+	Media      MediaMetadata `gorm:"embedded;embeddedPrefix:media_" json:"media"`
+
+	// ContentVersion naik setiap kali isi materi berubah (lihat MaterialVersion).
+	ContentVersion int `gorm:"default:1" json:"content_version"`
+
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+
+	IsCompleted bool   `gorm:"-" json:"is_completed"`
+	IsLocked    bool   `gorm:"-" json:"is_locked"`
+	LockReason  string `gorm:"-" json:"lock_reason,omitempty"`
+
+	Progress *MaterialProgress `gorm:"-" json:"progress,omitempty"` // Progres mahasiswa yang sedang melihat
 
 	SmartFeature *SmartFeature `gorm:"foreignKey:MaterialID" json:"smart_feature,omitempty"`
 }
@@ -68,4 +145,9 @@ type SmartFeature struct {
 	QuizData datatypes.JSON `json:"quiz_data"`
 
 	IsGenerated bool `gorm:"default:false" json:"is_generated"`
+
+	// SourceVersion adalah Material.ContentVersion saat fitur ini dibuat. IsStale = true jika
+	// materi sudah berubah sejak itu dan ringkasan perlu dibuat ulang.
+	SourceVersion int  `gorm:"default:1" json:"source_version"`
+	IsStale       bool `gorm:"default:false" json:"is_stale"`
 }
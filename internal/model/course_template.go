@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type TemplateVisibility string
+
+const (
+	TemplatePrivate TemplateVisibility = "private" // Hanya pembuat template
+	TemplatePublic  TemplateVisibility = "public"  // Semua dosen
+)
+
+// CourseTemplate adalah salinan isi kelas (modul, materi, ringkasan AI dan tugas) yang bisa
+// dipakai ulang untuk membuat kelas baru. Isi disimpan sebagai snapshot JSON di Content,
+// sehingga template tidak berubah walaupun kelas sumbernya diedit atau dihapus.
+type CourseTemplate struct {
+	ID              uint64             `gorm:"primaryKey;autoIncrement" json:"id"`
+	AuthorID        uint64             `gorm:"index" json:"author_id"`
+	Author          *User              `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	SourceCourseID  *uint64            `json:"source_course_id"`
+	Title           string             `gorm:"type:varchar(255)" json:"title"`
+	Description     string             `gorm:"type:text" json:"description"`
+	Thumbnail       string             `gorm:"type:varchar(255)" json:"thumbnail"`
+	Visibility      TemplateVisibility `gorm:"type:varchar(20);default:'private';index" json:"visibility"`
+	Content         datatypes.JSON     `json:"content,omitempty"`
+	ModuleCount     int                `json:"module_count"`
+	MaterialCount   int                `json:"material_count"`
+	AssignmentCount int                `json:"assignment_count"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
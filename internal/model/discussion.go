@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// DiscussionThread adalah topik diskusi di sebuah kelas. MaterialID terisi jika diskusi melekat
+// pada materi tertentu; kosong berarti diskusi umum kelas.
+type DiscussionThread struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID        uint64     `gorm:"index" json:"course_id"`
+	Course          *Course    `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	MaterialID      *uint64    `gorm:"index" json:"material_id"`
+	Material        *Material  `gorm:"foreignKey:MaterialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	AuthorID        uint64     `json:"author_id"`
+	Author          *User      `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Title           string     `gorm:"type:varchar(255)" json:"title"`
+	Body            string     `gorm:"type:text" json:"body"`
+	IsLocked        bool       `gorm:"default:false" json:"is_locked"` // Tidak menerima balasan baru
+	IsHidden        bool       `gorm:"default:false" json:"is_hidden"` // Disembunyikan moderator dari mahasiswa
+	AnsweredReplyID *uint64    `json:"answered_reply_id"`              // Balasan yang ditandai pengajar sebagai jawaban
+	ReplyCount      int        `gorm:"default:0" json:"reply_count"`
+	LastActivityAt  time.Time  `gorm:"index" json:"last_activity_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	ModeratedByID   *uint64    `json:"moderated_by_id,omitempty"`
+	ModeratedAt     *time.Time `json:"moderated_at,omitempty"`
+
+	Replies []DiscussionReply `gorm:"foreignKey:ThreadID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"replies,omitempty"`
+}
+
+// DiscussionReply adalah balasan pada thread. ParentID terisi untuk balasan atas balasan lain.
+// Balasan boleh berupa voice note (mahasiswa tuna wicara / tuna daksa) dengan transkrip opsional.
+type DiscussionReply struct {
+	ID               uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ThreadID         uint64     `gorm:"index" json:"thread_id"`
+	ParentID         *uint64    `gorm:"index" json:"parent_id"`
+	AuthorID         uint64     `json:"author_id"`
+	Author           *User      `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Body             string     `gorm:"type:text" json:"body"`
+	VoiceNoteURL     string     `gorm:"type:text" json:"voice_note_url,omitempty"`
+	VoiceDurationSec int        `json:"voice_duration_sec,omitempty"`
+	Transcript       string     `gorm:"type:text" json:"transcript,omitempty"` // Alternatif teks voice note
+	IsAnswer         bool       `gorm:"default:false" json:"is_answer"`
+	IsHidden         bool       `gorm:"default:false" json:"is_hidden"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	ModeratedByID    *uint64    `json:"moderated_by_id,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+}
+
+// DiscussionMention mencatat user yang disebut di thread atau balasan.
+type DiscussionMention struct {
+	ID            uint64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        uint64            `gorm:"index" json:"user_id"`
+	User          *User             `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ThreadID      uint64            `gorm:"index" json:"thread_id"`
+	Thread        *DiscussionThread `gorm:"foreignKey:ThreadID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"thread,omitempty"`
+	ReplyID       *uint64           `json:"reply_id"`
+	Reply         *DiscussionReply  `gorm:"foreignKey:ReplyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	MentionedByID uint64            `json:"mentioned_by_id"`
+	ReadAt        *time.Time        `json:"read_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type EnrollmentRequestStatus string
+
+const (
+	EnrollmentPending  EnrollmentRequestStatus = "pending"
+	EnrollmentApproved EnrollmentRequestStatus = "approved"
+	EnrollmentRejected EnrollmentRequestStatus = "rejected"
+)
+
+// EnrollmentRequest adalah permintaan bergabung ke kelas dengan kebijakan EnrollmentApproval.
+// Satu baris per mahasiswa per kelas; permintaan ulang setelah ditolak memakai baris yang sama.
+type EnrollmentRequest struct {
+	ID          uint64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID    uint64                  `gorm:"uniqueIndex:idx_enrollment_request" json:"course_id"`
+	Course      *Course                 `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"course,omitempty"`
+	UserID      uint64                  `gorm:"uniqueIndex:idx_enrollment_request;index" json:"user_id"`
+	User        *User                   `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user,omitempty"`
+	Status      EnrollmentRequestStatus `gorm:"type:varchar(20);index" json:"status"`
+	DecidedByID *uint64                 `json:"decided_by_id"`
+	DecidedAt   *time.Time              `json:"decided_at"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// CourseInvitation adalah undangan bergabung ke kelas yang dikirim ke email. Tautan undangan
+// berisi token bertanda tangan yang merujuk ID undangan dan TokenID; mengirim ulang undangan
+// mengganti TokenID sehingga tautan lama tidak berlaku.
+type CourseInvitation struct {
+	ID           uint64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID     uint64           `gorm:"index" json:"course_id"`
+	Course       *Course          `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"course,omitempty"`
+	Email        string           `gorm:"type:varchar(255);index" json:"email"`
+	TokenID      string           `gorm:"type:varchar(64)" json:"-"`
+	Status       InvitationStatus `gorm:"type:varchar(20);index" json:"status"`
+	InvitedByID  uint64           `json:"invited_by_id"`
+	InvitedBy    *User            `gorm:"foreignKey:InvitedByID" json:"invited_by,omitempty"`
+	AcceptedByID *uint64          `json:"accepted_by_id"`
+	AcceptedAt   *time.Time       `json:"accepted_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
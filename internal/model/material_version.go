@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// MaterialVersion adalah salinan isi materi pada satu versi. Baris tidak pernah diubah;
+// rollback membuat versi baru berisi salinan versi lama.
+type MaterialVersion struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	MaterialID uint64    `gorm:"uniqueIndex:idx_material_version" json:"material_id"`
+	Material   *Material `gorm:"foreignKey:MaterialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Version    int       `gorm:"uniqueIndex:idx_material_version" json:"version"`
+
+	Title       string       `gorm:"type:varchar(255)" json:"title"`
+	Type        MaterialType `gorm:"type:varchar(20)" json:"type"`
+	SourceURL   string       `gorm:"type:text" json:"source_url"`
+	RawContent  string       `gorm:"type:text" json:"raw_content"`
+	DurationMin int          `json:"duration_min"`
+	HasCaptions bool         `json:"has_captions"`
+	AltText     string       `gorm:"type:text" json:"alt_text,omitempty"`
+	Transcript  string       `gorm:"type:text" json:"transcript,omitempty"`
+
+	EditedByID *uint64   `gorm:"index" json:"edited_by_id"`
+	EditedBy   *User     `gorm:"foreignKey:EditedByID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"edited_by,omitempty"`
+	Note       string    `gorm:"type:varchar(255)" json:"note,omitempty"` // Mis. "rollback ke versi 2"
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -12,3 +12,26 @@ type MaterialCompletion struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
+
+// MaterialProgress merekam keterlibatan mahasiswa pada materi dari heartbeat klien: waktu belajar,
+// posisi terakhir (untuk melanjutkan) dan posisi terjauh (untuk penyelesaian otomatis).
+type MaterialProgress struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64 `gorm:"uniqueIndex:idx_progress_user_material" json:"user_id"`
+	MaterialID uint64 `gorm:"uniqueIndex:idx_progress_user_material" json:"material_id"`
+
+	TimeSpentSec int `gorm:"default:0" json:"time_spent_sec"`
+
+	LastPositionSec int        `gorm:"default:0" json:"last_position_sec"` // youtube / audio
+	LastPage        int        `gorm:"default:0" json:"last_page"`         // pdf / slides
+	LastScrollPct   float64    `gorm:"default:0" json:"last_scroll_pct"`   // text
+	MaxPositionSec  int        `gorm:"default:0" json:"max_position_sec"`
+	MaxPage         int        `gorm:"default:0" json:"max_page"`
+	MaxScrollPct    float64    `gorm:"default:0" json:"max_scroll_pct"`
+	ProgressPercent float64    `gorm:"default:0" json:"progress_percent"` // 0-100 menuju ambang selesai
+	AutoCompletedAt *time.Time `json:"auto_completed_at"`                 // Sekali saja; tanda selesai manual tetap dihormati
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
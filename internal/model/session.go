@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// UserSession mewakili satu sesi login. Access token membawa ID sesi (klaim "sid"),
+// sehingga mencabut sesi langsung membuat access token terkait ditolak.
+type UserSession struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint64     `gorm:"index" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserAgent string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress string     `gorm:"type:varchar(64)" json:"ip_address"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// RefreshToken disimpan dalam bentuk hash. Setiap kali dipakai, token lama ditandai
+// UsedAt dan token baru diterbitkan untuk sesi yang sama (rotasi).
+type RefreshToken struct {
+	ID        uint64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID uint64       `gorm:"index" json:"session_id"`
+	Session   *UserSession `gorm:"foreignKey:SessionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	TokenHash string       `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
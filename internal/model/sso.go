@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// UserIdentity menautkan akun lokal dengan identitas di penyedia SSO (OIDC).
+type UserIdentity struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      uint64     `gorm:"index" json:"user_id"`
+	User        *User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Provider    string     `gorm:"type:varchar(50);uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject     string     `gorm:"type:varchar(255);uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email       string     `gorm:"type:varchar(255)" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SSOLoginState menyimpan state, nonce dan PKCE verifier selama user berada di halaman penyedia SSO.
+type SSOLoginState struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider     string    `gorm:"type:varchar(50)" json:"provider"`
+	StateHash    string    `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	Nonce        string    `gorm:"type:varchar(64)" json:"-"`
+	CodeVerifier string    `gorm:"type:varchar(128)" json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
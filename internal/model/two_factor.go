@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// TwoFactorRecoveryCode adalah kode cadangan sekali pakai untuk login saat authenticator tidak tersedia.
+type TwoFactorRecoveryCode struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint64     `gorm:"index" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CodeHash  string     `gorm:"type:varchar(64);index" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -13,21 +13,40 @@ const (
 )
 
 type User struct {
-	ID                uint64                `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name              string                `gorm:"type:varchar(255)" json:"name"`
-	Email             string                `gorm:"type:varchar(255);unique" json:"email"`
-	Password          string                `gorm:"type:varchar(255)" json:"-"`
-	Role              UserRole              `gorm:"type:varchar(20)" json:"role"`
-	Avatar            string                `gorm:"type:varchar(255)" json:"avatar"`
-	Points            int                   `gorm:"default:0" json:"points"`
-	CurrentStreak     int                   `gorm:"default:0" json:"current_streak"`
-	LastActivityDate  time.Time             `gorm:"type:date" json:"last_activity_date"`
-	CreatedByID       *uint64               `json:"created_by_id"` // Tracks who created this user (e.g. Lecturer)
-	CreatedAt         time.Time             `json:"created_at"`
-	UpdatedAt         time.Time             `json:"updated_at"`
-	Accessibility     *AccessibilityProfile `gorm:"foreignKey:UserID" json:"accessibility_profile"`
-	IsVerified        bool                  `gorm:"default:false" json:"is_verified"`
-	VerificationToken string                `gorm:"type:varchar(255)" json:"-"`
+	ID                    uint64                `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                  string                `gorm:"type:varchar(255)" json:"name"`
+	Email                 string                `gorm:"type:varchar(255);unique" json:"email"`
+	Password              string                `gorm:"type:varchar(255)" json:"-"`
+	Role                  UserRole              `gorm:"type:varchar(20)" json:"role"`
+	Avatar                string                `gorm:"type:varchar(255)" json:"avatar"`
+	Points                int                   `gorm:"default:0" json:"points"`
+	CurrentStreak         int                   `gorm:"default:0" json:"current_streak"`
+	LastActivityDate      time.Time             `gorm:"type:date" json:"last_activity_date"`
+	CreatedByID           *uint64               `json:"created_by_id"` // Tracks who created this user (e.g. Lecturer)
+	CreatedAt             time.Time             `json:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at"`
+	Accessibility         *AccessibilityProfile `gorm:"foreignKey:UserID" json:"accessibility_profile"`
+	IsVerified            bool                  `gorm:"default:false" json:"is_verified"`
+	VerificationToken     string                `gorm:"type:varchar(255)" json:"-"`
+	VerificationExpiresAt *time.Time            `json:"-"`
+	VerificationSentAt    *time.Time            `json:"-"` // Used to throttle resend-verification requests
+	FailedLoginAttempts   int                   `gorm:"default:0" json:"-"`
+	LockedUntil           *time.Time            `json:"locked_until,omitempty"`
+	MustChangePassword    bool                  `gorm:"default:false" json:"must_change_password"` // Seeded / lecturer-created accounts with a default password
+	DeactivatedAt         *time.Time            `json:"deactivated_at"`                            // Set by admin; deactivated users cannot log in
+	TwoFactorSecret       string                `gorm:"type:varchar(64)" json:"-"`                 // Base32 TOTP secret; set during setup, active once TwoFactorEnabled
+	TwoFactorEnabled      bool                  `gorm:"default:false" json:"two_factor_enabled"`
+	TwoFactorLastStep     int64                 `gorm:"default:0" json:"-"`      // Last accepted TOTP time-step, rejects code replay
+	AnonymizedAt          *time.Time            `json:"anonymized_at,omitempty"` // Account deleted; row kept so submissions/activities are not orphaned
+}
+
+type LoginAttempt struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email     string    `gorm:"type:varchar(255);index" json:"email"`
+	UserID    *uint64   `json:"user_id"`
+	IPAddress string    `gorm:"type:varchar(64);index" json:"ip_address"`
+	Success   bool      `json:"success"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
 }
 
 type FriendshipStatus string
@@ -12,9 +12,9 @@ func CreateActivity(activity *model.Activity) error {
 func GetActivitiesByTeacherID(teacherID uint64, limit int) ([]model.Activity, error) {
 	var activities []model.Activity
 
-	// Join with Course to check TeacherID
+	// Join with Course to check the teacher (owner or staff)
 	err := database.DB.Joins("JOIN courses ON courses.id = activities.course_id").
-		Where("courses.teacher_id = ?", teacherID).
+		Where("courses.id IN (?)", taughtCourseIDs(teacherID)).
 		Preload("User").
 		Preload("Course").
 		Order("activities.created_at desc").
@@ -30,7 +30,7 @@ func GetActivitiesByTeacherIDWithPagination(teacherID uint64, limit int, offset
 
 	query := database.DB.Model(&model.Activity{}).
 		Joins("JOIN courses ON courses.id = activities.course_id").
-		Where("courses.teacher_id = ?", teacherID)
+		Where("courses.id IN (?)", taughtCourseIDs(teacherID))
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
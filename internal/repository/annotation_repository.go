@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+)
+
+func CreateAnnotation(annotation *model.MaterialAnnotation) error {
+	return database.DB.Create(annotation).Error
+}
+
+func UpdateAnnotation(annotation *model.MaterialAnnotation) error {
+	return database.DB.Save(annotation).Error
+}
+
+func DeleteAnnotation(id uint64) error {
+	return database.DB.Delete(&model.MaterialAnnotation{}, id).Error
+}
+
+func FindAnnotationByID(id uint64) (*model.MaterialAnnotation, error) {
+	var annotation model.MaterialAnnotation
+	err := database.DB.First(&annotation, id).Error
+	return &annotation, err
+}
+
+// GetMaterialAnnotations mengambil catatan dan sorotan user pada satu materi, urut posisi.
+func GetMaterialAnnotations(userID, materialID uint64) ([]model.MaterialAnnotation, error) {
+	var annotations []model.MaterialAnnotation
+	err := database.DB.
+		Where("user_id = ? AND material_id = ?", userID, materialID).
+		Order("anchor_page asc nulls first, anchor_start_offset asc nulls first, anchor_start_sec asc nulls first, created_at asc").
+		Find(&annotations).Error
+	return annotations, err
+}
+
+// GetCourseAnnotations mengambil seluruh catatan dan sorotan user pada materi-materi di kelas.
+func GetCourseAnnotations(userID, courseID uint64) ([]model.MaterialAnnotation, error) {
+	var annotations []model.MaterialAnnotation
+	err := database.DB.
+		Joins("JOIN materials ON materials.id = material_annotations.material_id").
+		Joins("JOIN modules ON modules.id = materials.module_id").
+		Where("material_annotations.user_id = ? AND modules.course_id = ?", userID, courseID).
+		Order("material_annotations.anchor_page asc nulls first, material_annotations.anchor_start_offset asc nulls first, material_annotations.anchor_start_sec asc nulls first, material_annotations.created_at asc").
+		Find(&annotations).Error
+	return annotations, err
+}
+
+// GetUserAnnotations mengambil semua catatan dan sorotan user (untuk ekspor data pribadi).
+func GetUserAnnotations(userID uint64) ([]model.MaterialAnnotation, error) {
+	var annotations []model.MaterialAnnotation
+	err := database.DB.Where("user_id = ?", userID).Order("created_at asc").Find(&annotations).Error
+	return annotations, err
+}
+
+func CreateBookmark(bookmark *model.MaterialBookmark) error {
+	return database.DB.Create(bookmark).Error
+}
+
+func DeleteBookmark(id uint64) error {
+	return database.DB.Delete(&model.MaterialBookmark{}, id).Error
+}
+
+func FindBookmarkByID(id uint64) (*model.MaterialBookmark, error) {
+	var bookmark model.MaterialBookmark
+	err := database.DB.First(&bookmark, id).Error
+	return &bookmark, err
+}
+
+func GetMaterialBookmarks(userID, materialID uint64) ([]model.MaterialBookmark, error) {
+	var bookmarks []model.MaterialBookmark
+	err := database.DB.
+		Where("user_id = ? AND material_id = ?", userID, materialID).
+		Order("created_at asc").
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// GetUserBookmarks mengambil bookmark user beserta materinya; courseID = 0 berarti semua kelas.
+func GetUserBookmarks(userID, courseID uint64) ([]model.MaterialBookmark, error) {
+	var bookmarks []model.MaterialBookmark
+	query := database.DB.Preload("Material").Where("material_bookmarks.user_id = ?", userID)
+	if courseID != 0 {
+		query = query.
+			Joins("JOIN materials ON materials.id = material_bookmarks.material_id").
+			Joins("JOIN modules ON modules.id = materials.module_id").
+			Where("modules.course_id = ?", courseID)
+	}
+	err := query.Order("material_bookmarks.created_at desc").Find(&bookmarks).Error
+	return bookmarks, err
+}
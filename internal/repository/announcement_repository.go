@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// announcementOrder menaruh pengumuman yang disematkan di atas, lalu yang terbaru.
+func announcementOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("pinned desc, publish_at desc, id desc")
+}
+
+func CreateAnnouncement(announcement *model.Announcement) error {
+	return database.DB.Create(announcement).Error
+}
+
+// UpdateAnnouncement menyimpan perubahan pengumuman dan mengganti seluruh lampirannya.
+func UpdateAnnouncement(announcement *model.Announcement) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(announcement).
+			Select("title", "content", "pinned", "publish_at").
+			Updates(announcement).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&model.AnnouncementAttachment{}).Error; err != nil {
+			return err
+		}
+		for i := range announcement.Attachments {
+			announcement.Attachments[i].ID = 0
+			announcement.Attachments[i].AnnouncementID = announcement.ID
+		}
+		if len(announcement.Attachments) > 0 {
+			return tx.Create(&announcement.Attachments).Error
+		}
+		return nil
+	})
+}
+
+func DeleteAnnouncement(id uint64) error {
+	return database.DB.Delete(&model.Announcement{}, id).Error
+}
+
+func FindAnnouncementByID(id uint64) (*model.Announcement, error) {
+	var announcement model.Announcement
+	err := database.DB.Preload("Attachments").Preload("Author").First(&announcement, id).Error
+	return &announcement, err
+}
+
+// GetCourseAnnouncements mengembalikan pengumuman kelas. publishedBefore != nil membatasi ke
+// pengumuman yang sudah terbit pada waktu tersebut (tampilan mahasiswa).
+func GetCourseAnnouncements(courseID uint64, publishedBefore *time.Time) ([]model.Announcement, error) {
+	var announcements []model.Announcement
+	query := database.DB.Preload("Attachments").Preload("Author").Where("course_id = ?", courseID)
+	if publishedBefore != nil {
+		query = query.Where("publish_at <= ?", *publishedBefore)
+	}
+	err := query.Scopes(announcementOrder).Find(&announcements).Error
+	return announcements, err
+}
+
+func GetReadAnnouncementIDs(userID uint64, announcementIDs []uint64) (map[uint64]bool, error) {
+	read := make(map[uint64]bool)
+	if len(announcementIDs) == 0 {
+		return read, nil
+	}
+
+	var ids []uint64
+	err := database.DB.Model(&model.AnnouncementRead{}).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Pluck("announcement_id", &ids).Error
+	for _, id := range ids {
+		read[id] = true
+	}
+	return read, err
+}
+
+func MarkAnnouncementRead(announcementID, userID uint64) error {
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.AnnouncementRead{
+		AnnouncementID: announcementID,
+		UserID:         userID,
+		ReadAt:         time.Now(),
+	}).Error
+}
@@ -33,7 +33,8 @@ func GetAssignmentsByStudentID(studentID uint64, statusFilter string) ([]model.A
 	query := database.DB.Table("assignments").
 		Joins("JOIN courses ON assignments.course_id = courses.id").
 		Joins("JOIN course_students ON courses.id = course_students.course_id").
-		Where("course_students.user_id = ?", studentID)
+		Where("course_students.user_id = ?", studentID).
+		Where("courses.status <> ?", model.CourseStatusArchived)
 
 	if statusFilter == "overdue" {
 		query = query.Where("assignments.deadline < ?", time.Now())
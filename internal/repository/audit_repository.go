@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+)
+
+type AuditLogFilter struct {
+	ActorID    *uint64
+	Action     string
+	TargetType string
+	TargetID   *uint64
+	CourseID   *uint64
+	From       *time.Time
+	To         *time.Time
+}
+
+// CreateAuditLog adalah satu-satunya operasi tulis untuk audit log (append-only).
+func CreateAuditLog(log *model.AuditLog) error {
+	return database.DB.Create(log).Error
+}
+
+func FindAuditLogs(filter AuditLogFilter, limit int, offset int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+
+	query := database.DB.Model(&model.AuditLog{})
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		// "user." cocok dengan semua aksi user.*
+		query = query.Where("action LIKE ?", filter.Action+"%")
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Actor").
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&logs).Error
+
+	return logs, total, err
+}
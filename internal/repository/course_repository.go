@@ -13,7 +13,7 @@ func CreateCourse(course *model.Course) error {
 
 func GetCoursesByTeacherID(teacherID uint64, search string, status string, sort string) ([]model.Course, error) {
 	var courses []model.Course
-	query := database.DB.Where("teacher_id = ?", teacherID)
+	query := database.DB.Where("id IN (?)", taughtCourseIDs(teacherID))
 
 	if search != "" {
 		query = query.Where("title LIKE ?", "%"+search+"%")
@@ -35,12 +35,23 @@ func GetCoursesByTeacherID(teacherID uint64, search string, status string, sort
 
 func GetCourseByID(id uint64) (*model.Course, error) {
 	var course model.Course
-	err := database.DB.Preload("Modules.Materials").First(&course, id).Error
+	err := database.DB.
+		Preload("Modules", orderByPosition).
+		Preload("Modules.Materials", orderByPosition).
+		First(&course, id).Error
 	return &course, err
 }
 
-func UpdateCourse(course *model.Course) error {
+// UpdateCourse menyimpan kelas beserta modul dan materinya. knownVersions berisi content_version
+// materi lama saat dibaca (materialID -> versi); baris materi itu dikunci dan ditolak jika sudah
+// berubah. versions dipanggil setelah materi baru mendapat ID, dan hasilnya disimpan dalam
+// transaksi yang sama.
+func UpdateCourse(course *model.Course, knownVersions map[uint64]int, versions func() []model.MaterialVersion) error {
 	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockMaterialVersions(tx, knownVersions); err != nil {
+			return err
+		}
+
 		// 1. Get IDs of ALL modules currently in DB for this course (to detect deletions)
 		var oldModuleIDs []uint64
 		if err := tx.Model(&model.Module{}).Where("course_id = ?", course.ID).Pluck("id", &oldModuleIDs).Error; err != nil {
@@ -87,6 +98,9 @@ func UpdateCourse(course *model.Course) error {
 					if err := tx.Where("material_id IN ?", mIDs).Delete(&model.MaterialCompletion{}).Error; err != nil {
 						return err
 					}
+					if err := tx.Where("material_id IN ?", mIDs).Delete(&model.MaterialProgress{}).Error; err != nil {
+						return err
+					}
 					if err := tx.Where("material_id IN ?", mIDs).Delete(&model.SmartFeature{}).Error; err != nil {
 						return err
 					}
@@ -130,6 +144,9 @@ func UpdateCourse(course *model.Course) error {
 							if err := tx.Where("material_id = ?", oldMatID).Delete(&model.MaterialCompletion{}).Error; err != nil {
 								return err
 							}
+							if err := tx.Where("material_id = ?", oldMatID).Delete(&model.MaterialProgress{}).Error; err != nil {
+								return err
+							}
 							if err := tx.Where("material_id = ?", oldMatID).Delete(&model.SmartFeature{}).Error; err != nil {
 								return err
 							}
@@ -143,7 +160,10 @@ func UpdateCourse(course *model.Course) error {
 			}
 		}
 
-		return nil
+		if versions == nil {
+			return nil
+		}
+		return insertMaterialVersions(tx, versions())
 	})
 }
 
@@ -169,12 +189,23 @@ func IsStudentInCourse(courseID, studentID uint64) (bool, error) {
 	return count > 0, err
 }
 
-func GetCoursesByStudentID(studentID uint64) ([]model.Course, error) {
+// GetCoursesByStudentID mengembalikan kelas yang diikuti mahasiswa. status: "" atau "active"
+// (tanpa kelas archived), "archived", atau "all".
+func GetCoursesByStudentID(studentID uint64, status string) ([]model.Course, error) {
 	var courses []model.Course
-	err := database.DB.Table("courses").
+	query := database.DB.Table("courses").
 		Joins("JOIN course_students ON courses.id = course_students.course_id").
-		Where("course_students.user_id = ?", studentID).
-		Find(&courses).Error
+		Where("course_students.user_id = ?", studentID)
+
+	switch status {
+	case "all":
+	case model.CourseStatusArchived:
+		query = query.Where("courses.status = ?", model.CourseStatusArchived)
+	default:
+		query = query.Where("courses.status <> ?", model.CourseStatusArchived)
+	}
+
+	err := query.Find(&courses).Error
 
 	if err != nil {
 		return nil, err
@@ -221,9 +252,10 @@ func calculateCourseProgress(courseID, studentID uint64) float64 {
 		Where("assignments.course_id = ? AND submissions.student_id = ?", courseID, studentID).
 		Count(&submittedAssignments)
 
-	completedItems := completedMaterials + submittedAssignments
+	// Materials in progress count partially (heartbeat progress toward auto-completion)
+	completedItems := float64(completedMaterials+submittedAssignments) + partialMaterialProgress(courseID, studentID)
 
-	return (float64(completedItems) / float64(totalItems)) * 100
+	return (completedItems / float64(totalItems)) * 100
 }
 
 func ToggleMaterialCompletion(userID, materialID uint64) (bool, error) {
@@ -300,6 +332,9 @@ func DeleteModule(id uint64) error {
 			if err := tx.Where("material_id IN ?", materialIDs).Delete(&model.MaterialCompletion{}).Error; err != nil {
 				return err
 			}
+			if err := tx.Where("material_id IN ?", materialIDs).Delete(&model.MaterialProgress{}).Error; err != nil {
+				return err
+			}
 			if err := tx.Where("material_id IN ?", materialIDs).Delete(&model.SmartFeature{}).Error; err != nil {
 				return err
 			}
@@ -324,6 +359,9 @@ func DeleteMaterial(id uint64) error {
 		if err := tx.Where("material_id = ?", id).Delete(&model.MaterialCompletion{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("material_id = ?", id).Delete(&model.MaterialProgress{}).Error; err != nil {
+			return err
+		}
 		if err := tx.Where("material_id = ?", id).Delete(&model.SmartFeature{}).Error; err != nil {
 			return err
 		}
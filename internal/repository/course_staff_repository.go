@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+func AddCourseStaff(member *model.CourseStaff) error {
+	return database.DB.Create(member).Error
+}
+
+func FindCourseStaff(courseID uint64, userID uint64) (*model.CourseStaff, error) {
+	var member model.CourseStaff
+	err := database.DB.Where("course_id = ? AND user_id = ?", courseID, userID).First(&member).Error
+	return &member, err
+}
+
+func GetCourseStaff(courseID uint64) ([]model.CourseStaff, error) {
+	var members []model.CourseStaff
+	err := database.DB.Preload("User").
+		Where("course_id = ?", courseID).
+		Order("created_at asc").
+		Find(&members).Error
+	return members, err
+}
+
+func UpdateCourseStaffRole(courseID uint64, userID uint64, role model.CourseStaffRole) error {
+	return database.DB.Model(&model.CourseStaff{}).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Update("role", role).Error
+}
+
+func RemoveCourseStaff(courseID uint64, userID uint64) error {
+	return database.DB.Where("course_id = ? AND user_id = ?", courseID, userID).Delete(&model.CourseStaff{}).Error
+}
+
+// taughtCourseIDs adalah subquery ID kelas yang diajar user, sebagai pemilik maupun staf pengajar.
+func taughtCourseIDs(teacherID uint64) *gorm.DB {
+	return database.DB.Table("courses").
+		Select("id").
+		Where("teacher_id = ?", teacherID).
+		Or("id IN (?)", database.DB.Table("course_staffs").Select("course_id").Where("user_id = ?", teacherID))
+}
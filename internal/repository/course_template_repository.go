@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// GetCourseForCopy memuat kelas beserta seluruh isi yang ikut disalin (tanpa mahasiswa dan pengumpulan).
+func GetCourseForCopy(id uint64) (*model.Course, error) {
+	var course model.Course
+	err := database.DB.
+		Preload("Modules", orderByPosition).
+		Preload("Modules.Materials", orderByPosition).
+		Preload("Modules.Materials.SmartFeature").
+		Preload("Assignments", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		First(&course, id).Error
+	return &course, err
+}
+
+// CreateCourseCopy membuat kelas (beserta modul, materi dan SmartFeature) lalu tugasnya dalam satu
+// transaksi. moduleIndex[i] adalah indeks di course.Modules yang ditautkan ke assignments[i],
+// atau -1 jika tugas tidak terkait modul.
+func CreateCourseCopy(course *model.Course, assignments []model.Assignment, moduleIndex []int) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(course).Error; err != nil {
+			return err
+		}
+
+		for i := range assignments {
+			assignments[i].CourseID = course.ID
+			if idx := moduleIndex[i]; idx >= 0 && idx < len(course.Modules) {
+				moduleID := course.Modules[idx].ID
+				assignments[i].ModuleID = &moduleID
+			}
+		}
+		if len(assignments) > 0 {
+			if err := tx.Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+
+		course.Assignments = assignments
+		return nil
+	})
+}
+
+func CreateCourseTemplate(template *model.CourseTemplate) error {
+	return database.DB.Create(template).Error
+}
+
+func FindCourseTemplateByID(id uint64) (*model.CourseTemplate, error) {
+	var template model.CourseTemplate
+	err := database.DB.Preload("Author").First(&template, id).Error
+	return &template, err
+}
+
+// GetVisibleCourseTemplates mengembalikan template milik user dan template publik, tanpa isi snapshot.
+func GetVisibleCourseTemplates(userID uint64, search string) ([]model.CourseTemplate, error) {
+	var templates []model.CourseTemplate
+	query := database.DB.Omit("content").Preload("Author").
+		Where("author_id = ? OR visibility = ?", userID, model.TemplatePublic)
+
+	if search != "" {
+		query = query.Where("title ILIKE ?", "%"+search+"%")
+	}
+
+	err := query.Order("created_at desc").Find(&templates).Error
+	return templates, err
+}
+
+func DeleteCourseTemplate(id uint64) error {
+	return database.DB.Delete(&model.CourseTemplate{}, id).Error
+}
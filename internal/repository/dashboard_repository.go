@@ -26,7 +26,7 @@ func GetActiveClassesByTeacherID(teacherID uint64) ([]ActiveClassResult, error)
 
 	err := database.DB.Table("courses").
 		Select("id, title, created_at").
-		Where("teacher_id = ? AND status = ?", teacherID, "published").
+		Where("id IN (?) AND status = ?", taughtCourseIDs(teacherID), "published").
 		Scan(&courses).Error
 	if err != nil {
 		return nil, err
@@ -69,10 +69,11 @@ func GetActiveClassesByTeacherID(teacherID uint64) ([]ActiveClassResult, error)
 				Where("assignments.course_id = ?", c.ID).
 				Count(&submittedAssignments)
 
-			totalCompleted := completedMaterials + submittedAssignments
+			// Materials in progress count partially (heartbeat progress toward auto-completion)
+			totalCompleted := float64(completedMaterials+submittedAssignments) + partialMaterialProgress(c.ID, 0)
 			totalPossible := totalItems * studentCount
 
-			progress = (float64(totalCompleted) / float64(totalPossible)) * 100
+			progress = (totalCompleted / float64(totalPossible)) * 100
 		}
 
 		results = append(results, ActiveClassResult{
@@ -114,7 +115,7 @@ func GetRecentActivitiesByTeacherID(teacherID uint64, limit int) ([]ActivityResu
 		Joins("JOIN users ON submissions.student_id = users.id").
 		Joins("JOIN assignments ON submissions.assignment_id = assignments.id").
 		Joins("JOIN courses ON assignments.course_id = courses.id").
-		Where("courses.teacher_id = ?", teacherID).
+		Where("courses.id IN (?)", taughtCourseIDs(teacherID)).
 		Order("submissions.submitted_at desc").
 		Limit(limit).
 		Scan(&subs).Error
@@ -151,7 +152,7 @@ func GetPendingAssignmentsByTeacherID(teacherID uint64) ([]PendingAssignmentResu
 		Select("assignments.id, assignments.title, courses.title as course_name, COUNT(submissions.id) as submitted_count").
 		Joins("JOIN courses ON assignments.course_id = courses.id").
 		Joins("JOIN submissions ON assignments.id = submissions.assignment_id").
-		Where("courses.teacher_id = ? AND submissions.grade = 0", teacherID).
+		Where("courses.id IN (?) AND submissions.grade = 0", taughtCourseIDs(teacherID)).
 		Group("assignments.id, assignments.title, courses.title").
 		Having("COUNT(submissions.id) > 0").
 		Rows()
@@ -176,7 +177,7 @@ func GetPendingAssignmentsByTeacherID(teacherID uint64) ([]PendingAssignmentResu
 
 func GetCourseCountByTeacherID(teacherID uint64) (int64, error) {
 	var count int64
-	err := database.DB.Table("courses").Where("teacher_id = ? AND status = ?", teacherID, "published").Count(&count).Error
+	err := database.DB.Table("courses").Where("id IN (?) AND status = ?", taughtCourseIDs(teacherID), "published").Count(&count).Error
 	return count, err
 }
 
@@ -184,7 +185,7 @@ func GetStudentCountByTeacherID(teacherID uint64) (int64, error) {
 	var count int64
 	err := database.DB.Table("course_students").
 		Joins("JOIN courses ON course_students.course_id = courses.id").
-		Where("courses.teacher_id = ? AND courses.status = ?", teacherID, "published").
+		Where("courses.id IN (?) AND courses.status = ?", taughtCourseIDs(teacherID), "published").
 		Distinct("course_students.user_id").
 		Count(&count).Error
 	return count, err
@@ -195,7 +196,7 @@ func GetUngradedAssignmentCountByTeacherID(teacherID uint64) (int64, error) {
 	err := database.DB.Table("submissions").
 		Joins("JOIN assignments ON submissions.assignment_id = assignments.id").
 		Joins("JOIN courses ON assignments.course_id = courses.id").
-		Where("courses.teacher_id = ? AND submissions.grade = 0", teacherID).
+		Where("courses.id IN (?) AND submissions.grade = 0", taughtCourseIDs(teacherID)).
 		Count(&count).Error
 	return count, err
 }
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DiscussionFilter membatasi daftar thread. MaterialID = nil dan GeneralOnly = false berarti
+// semua thread di kelas.
+type DiscussionFilter struct {
+	MaterialID    *uint64
+	GeneralOnly   bool // Hanya diskusi umum kelas (tanpa materi)
+	IncludeHidden bool
+}
+
+func createMentions(tx *gorm.DB, threadID uint64, replyID *uint64, mentions []model.DiscussionMention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	for i := range mentions {
+		mentions[i].ThreadID = threadID
+		mentions[i].ReplyID = replyID
+	}
+	return tx.Create(&mentions).Error
+}
+
+func CreateDiscussionThread(thread *model.DiscussionThread, mentions []model.DiscussionMention) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(thread).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, thread.ID, nil, mentions)
+	})
+}
+
+func FindDiscussionThreadByID(id uint64) (*model.DiscussionThread, error) {
+	var thread model.DiscussionThread
+	err := database.DB.Preload("Author").First(&thread, id).Error
+	return &thread, err
+}
+
+// GetDiscussionThreadWithReplies memuat thread beserta balasannya (urut waktu). Balasan yang
+// disembunyikan hanya ikut jika includeHidden.
+func GetDiscussionThreadWithReplies(id uint64, includeHidden bool) (*model.DiscussionThread, error) {
+	var thread model.DiscussionThread
+	err := database.DB.
+		Preload("Author").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			if !includeHidden {
+				db = db.Where("is_hidden = ?", false)
+			}
+			return db.Order("created_at asc, id asc")
+		}).
+		Preload("Replies.Author").
+		First(&thread, id).Error
+	return &thread, err
+}
+
+func GetDiscussionThreads(courseID uint64, filter DiscussionFilter) ([]model.DiscussionThread, error) {
+	var threads []model.DiscussionThread
+	query := database.DB.Preload("Author").Where("course_id = ?", courseID)
+	if filter.MaterialID != nil {
+		query = query.Where("material_id = ?", *filter.MaterialID)
+	} else if filter.GeneralOnly {
+		query = query.Where("material_id IS NULL")
+	}
+	if !filter.IncludeHidden {
+		query = query.Where("is_hidden = ?", false)
+	}
+	err := query.Order("last_activity_at desc, id desc").Find(&threads).Error
+	return threads, err
+}
+
+// CreateDiscussionReply menyimpan balasan dan memperbarui jumlah balasan serta waktu aktivitas
+// terakhir thread dalam satu transaksi.
+func CreateDiscussionReply(reply *model.DiscussionReply, mentions []model.DiscussionMention) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reply).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.DiscussionThread{}).Where("id = ?", reply.ThreadID).Updates(map[string]interface{}{
+			"reply_count":      gorm.Expr("reply_count + 1"),
+			"last_activity_at": reply.CreatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, reply.ThreadID, &reply.ID, mentions)
+	})
+}
+
+func FindDiscussionReplyByID(id uint64) (*model.DiscussionReply, error) {
+	var reply model.DiscussionReply
+	err := database.DB.Preload("Author").First(&reply, id).Error
+	return &reply, err
+}
+
+func UpdateDiscussionThreadFields(id uint64, fields map[string]interface{}) error {
+	return database.DB.Model(&model.DiscussionThread{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func UpdateDiscussionReplyFields(id uint64, fields map[string]interface{}) error {
+	return database.DB.Model(&model.DiscussionReply{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// SetDiscussionAnswer menandai replyID sebagai satu-satunya jawaban thread (nil = hapus tanda).
+func SetDiscussionAnswer(threadID uint64, replyID *uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.DiscussionReply{}).
+			Where("thread_id = ? AND is_answer = ?", threadID, true).
+			Update("is_answer", false).Error; err != nil {
+			return err
+		}
+		if replyID != nil {
+			if err := tx.Model(&model.DiscussionReply{}).Where("id = ?", *replyID).Update("is_answer", true).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.DiscussionThread{}).Where("id = ?", threadID).Update("answered_reply_id", replyID).Error
+	})
+}
+
+func GetDiscussionMentions(userID uint64, unreadOnly bool) ([]model.DiscussionMention, error) {
+	var mentions []model.DiscussionMention
+	query := database.DB.Preload("Thread").Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at desc").Limit(100).Find(&mentions).Error
+	return mentions, err
+}
+
+// MarkDiscussionMentionsRead menandai mention milik user sudah dibaca; ids kosong = semua.
+func MarkDiscussionMentionsRead(userID uint64, ids []uint64) error {
+	query := database.DB.Model(&model.DiscussionMention{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	return query.Update("read_at", time.Now()).Error
+}
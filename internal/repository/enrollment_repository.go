@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCourseFull = errors.New("kelas sudah penuh")
+
+// lockCourseCapacity mengunci baris kelas sampai transaksi selesai lalu memastikan masih ada
+// kursi, sehingga pendaftaran yang berjalan bersamaan tidak bisa melewati MaxStudents.
+func lockCourseCapacity(tx *gorm.DB, courseID uint64) error {
+	var course model.Course
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "max_students").
+		First(&course, courseID).Error; err != nil {
+		return err
+	}
+	if course.MaxStudents <= 0 {
+		return nil
+	}
+
+	var count int64
+	if err := tx.Table("course_students").Where("course_id = ?", courseID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= int64(course.MaxStudents) {
+		return ErrCourseFull
+	}
+	return nil
+}
+
+// AddStudentWithinCapacity memasukkan mahasiswa ke kelas jika kapasitas masih tersedia.
+// Pengecekan dan insert berjalan dalam satu transaksi dengan baris kelas terkunci.
+func AddStudentWithinCapacity(courseID, studentID uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockCourseCapacity(tx, courseID); err != nil {
+			return err
+		}
+		return tx.Exec(
+			"INSERT INTO course_students (course_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			courseID, studentID,
+		).Error
+	})
+}
+
+func CountStudentsInCourse(courseID uint64) (int64, error) {
+	var count int64
+	err := database.DB.Table("course_students").Where("course_id = ?", courseID).Count(&count).Error
+	return count, err
+}
+
+func RemoveStudentFromCourse(courseID, studentID uint64) error {
+	return database.DB.Exec("DELETE FROM course_students WHERE course_id = ? AND user_id = ?", courseID, studentID).Error
+}
+
+// UpdateCourseFields memperbarui kolom kelas tertentu saja tanpa menyentuh modul dan materi.
+func UpdateCourseFields(id uint64, fields map[string]interface{}) error {
+	return database.DB.Model(&model.Course{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func FindEnrollmentRequest(courseID, userID uint64) (*model.EnrollmentRequest, error) {
+	var request model.EnrollmentRequest
+	err := database.DB.Where("course_id = ? AND user_id = ?", courseID, userID).First(&request).Error
+	return &request, err
+}
+
+func FindEnrollmentRequestByID(id uint64) (*model.EnrollmentRequest, error) {
+	var request model.EnrollmentRequest
+	err := database.DB.Preload("User").First(&request, id).Error
+	return &request, err
+}
+
+func SaveEnrollmentRequest(request *model.EnrollmentRequest) error {
+	return database.DB.Save(request).Error
+}
+
+func GetEnrollmentRequestsByCourse(courseID uint64, status string) ([]model.EnrollmentRequest, error) {
+	var requests []model.EnrollmentRequest
+	query := database.DB.Preload("User").Where("course_id = ?", courseID)
+	if status != "" && status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at asc").Find(&requests).Error
+	return requests, err
+}
+
+func GetEnrollmentRequestsByUser(userID uint64) ([]model.EnrollmentRequest, error) {
+	var requests []model.EnrollmentRequest
+	err := database.DB.Preload("Course").
+		Where("user_id = ?", userID).
+		Order("updated_at desc").
+		Find(&requests).Error
+	return requests, err
+}
+
+// DecideEnrollmentRequest menyimpan keputusan pengajar. Jika disetujui, mahasiswa langsung
+// dimasukkan ke kelas dalam transaksi yang sama, dengan kapasitas kelas dicek di bawah kunci.
+func DecideEnrollmentRequest(request *model.EnrollmentRequest, status model.EnrollmentRequestStatus, deciderID uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if status == model.EnrollmentApproved {
+			if err := lockCourseCapacity(tx, request.CourseID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(request).Updates(map[string]interface{}{
+			"status":        status,
+			"decided_by_id": deciderID,
+			"decided_at":    now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if status == model.EnrollmentApproved {
+			if err := tx.Exec(
+				"INSERT INTO course_students (course_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				request.CourseID, request.UserID,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		request.Status = status
+		request.DecidedByID = &deciderID
+		request.DecidedAt = &now
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+)
+
+func SaveCourseInvitation(invitation *model.CourseInvitation) error {
+	return database.DB.Save(invitation).Error
+}
+
+func FindCourseInvitationByID(id uint64) (*model.CourseInvitation, error) {
+	var invitation model.CourseInvitation
+	err := database.DB.Preload("Course").Preload("InvitedBy").First(&invitation, id).Error
+	return &invitation, err
+}
+
+// FindPendingCourseInvitation mencari undangan yang masih menunggu untuk email di kelas tertentu.
+func FindPendingCourseInvitation(courseID uint64, email string) (*model.CourseInvitation, error) {
+	var invitation model.CourseInvitation
+	err := database.DB.
+		Where("course_id = ? AND email = ? AND status = ?", courseID, email, model.InvitationPending).
+		First(&invitation).Error
+	return &invitation, err
+}
+
+func GetCourseInvitations(courseID uint64, status string) ([]model.CourseInvitation, error) {
+	var invitations []model.CourseInvitation
+	query := database.DB.Preload("InvitedBy").Where("course_id = ?", courseID)
+	if status != "" && status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at desc").Find(&invitations).Error
+	return invitations, err
+}
+
+func UpdateCourseInvitationStatus(id uint64, status model.InvitationStatus) error {
+	return database.DB.Model(&model.CourseInvitation{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func MarkCourseInvitationAccepted(invitation *model.CourseInvitation, userID uint64) error {
+	now := time.Now()
+	if err := database.DB.Model(invitation).Updates(map[string]interface{}{
+		"status":         model.InvitationAccepted,
+		"accepted_by_id": userID,
+		"accepted_at":    now,
+	}).Error; err != nil {
+		return err
+	}
+
+	invitation.Status = model.InvitationAccepted
+	invitation.AcceptedByID = &userID
+	invitation.AcceptedAt = &now
+	return nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+)
+
+func CreateLoginAttempt(attempt *model.LoginAttempt) error {
+	return database.DB.Create(attempt).Error
+}
+
+func CountFailedLoginAttemptsByIP(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := database.DB.Model(&model.LoginAttempt{}).
+		Where("ip_address = ? AND success = ? AND created_at >= ?", ip, false, since).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrMaterialVersionConflict = errors.New("materi baru saja diubah oleh pengguna lain. muat ulang lalu coba lagi")
+
+// lockMaterialVersions mengunci baris materi sampai transaksi selesai dan memastikan
+// content_version-nya masih sama dengan yang dibaca pemanggil (materialID -> versi). Tanpa ini dua
+// editor bisa menghitung nomor versi yang sama dan salah satu revisi hilang tanpa jejak.
+func lockMaterialVersions(tx *gorm.DB, expected map[uint64]int) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	ids := make([]uint64, 0, len(expected))
+	for id := range expected {
+		ids = append(ids, id)
+	}
+
+	var materials []model.Material
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "content_version").
+		Where("id IN ?", ids).
+		Order("id asc").
+		Find(&materials).Error; err != nil {
+		return err
+	}
+	for _, m := range materials {
+		if max(m.ContentVersion, 1) != max(expected[m.ID], 1) {
+			return ErrMaterialVersionConflict
+		}
+	}
+	return nil
+}
+
+func GetMaterialVersions(materialID uint64) ([]model.MaterialVersion, error) {
+	var versions []model.MaterialVersion
+	err := database.DB.
+		Preload("EditedBy", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name", "email") }).
+		Where("material_id = ?", materialID).
+		Order("version desc").
+		Find(&versions).Error
+	return versions, err
+}
+
+func FindMaterialVersion(materialID uint64, version int) (*model.MaterialVersion, error) {
+	var v model.MaterialVersion
+	err := database.DB.
+		Preload("EditedBy", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name", "email") }).
+		Where("material_id = ? AND version = ?", materialID, version).
+		First(&v).Error
+	return &v, err
+}
+
+// insertMaterialVersions menyimpan versi yang belum ada (versi lama yang sudah tercatat dilewati)
+// dan menandai SmartFeature materi tersebut usang.
+func insertMaterialVersions(tx *gorm.DB, versions []model.MaterialVersion) error {
+	for i := range versions {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&versions[i]).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.SmartFeature{}).
+			Where("material_id = ? AND source_version < ?", versions[i].MaterialID, versions[i].Version).
+			Update("is_stale", true).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CreateMaterialVersions(versions []model.MaterialVersion) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return insertMaterialVersions(tx, versions)
+	})
+}
+
+// UpdateMaterialWithVersions menyimpan materi dan versi barunya dalam satu transaksi.
+// previousVersion adalah content_version materi saat dibaca sebelum diubah.
+func UpdateMaterialWithVersions(material *model.Material, previousVersion int, versions []model.MaterialVersion) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockMaterialVersions(tx, map[uint64]int{material.ID: previousVersion}); err != nil {
+			return err
+		}
+		if err := tx.Omit("SmartFeature").Save(material).Error; err != nil {
+			return err
+		}
+		return insertMaterialVersions(tx, versions)
+	})
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// orderByPosition mengurutkan modul atau materi sesuai kolom "order" (kata kunci SQL, harus dikutip).
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order(`"order" asc, id asc`)
+}
+
+func CreateModule(module *model.Module) error {
+	return database.DB.Create(module).Error
+}
+
+func UpdateModule(module *model.Module) error {
+	return database.DB.Model(module).Select("title", "available_from", "available_until", "requires_previous").Updates(module).Error
+}
+
+func GetMaterialsByModuleID(moduleID uint64) ([]model.Material, error) {
+	var materials []model.Material
+	err := database.DB.Where("module_id = ?", moduleID).Scopes(orderByPosition).Find(&materials).Error
+	return materials, err
+}
+
+func NextModuleOrder(courseID uint64) (int, error) {
+	var maxOrder int
+	err := database.DB.Model(&model.Module{}).Where("course_id = ?", courseID).
+		Select(`COALESCE(MAX("order"), 0)`).Scan(&maxOrder).Error
+	return maxOrder + 1, err
+}
+
+func NextMaterialOrder(moduleID uint64) (int, error) {
+	var maxOrder int
+	err := database.DB.Model(&model.Material{}).Where("module_id = ?", moduleID).
+		Select(`COALESCE(MAX("order"), 0)`).Scan(&maxOrder).Error
+	return maxOrder + 1, err
+}
+
+// applyOrder menulis ulang kolom "order" menjadi 1..n sesuai urutan ids. ids harus berisi
+// tepat semua baris milik parent (parentColumn = parentID).
+func applyOrder(tx *gorm.DB, table interface{}, parentColumn string, parentID uint64, ids []uint64) error {
+	var existing []uint64
+	if err := tx.Model(table).Where(parentColumn+" = ?", parentID).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+
+	if len(existing) != len(ids) {
+		return errors.New("urutan tidak valid: daftar ID harus berisi semua item tepat satu kali")
+	}
+	remaining := make(map[uint64]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return errors.New("urutan tidak valid: daftar ID harus berisi semua item tepat satu kali")
+		}
+		delete(remaining, id)
+	}
+
+	for i, id := range ids {
+		if err := tx.Model(table).Where("id = ?", id).Update("order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ReorderModules(courseID uint64, moduleIDs []uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return applyOrder(tx, &model.Module{}, "course_id", courseID, moduleIDs)
+	})
+}
+
+func ReorderMaterials(moduleID uint64, materialIDs []uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return applyOrder(tx, &model.Material{}, "module_id", moduleID, materialIDs)
+	})
+}
+
+// MoveMaterial memindahkan materi ke targetModuleID pada posisi position (1-based; 0 atau lebih
+// dari jumlah materi = paling akhir). Urutan modul asal dan tujuan dirapikan dalam satu transaksi.
+// ID materi tidak berubah, sehingga progres mahasiswa dan ringkasan AI tetap utuh.
+func MoveMaterial(material *model.Material, targetModuleID uint64, position int) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		sourceModuleID := material.ModuleID
+
+		var targetIDs []uint64
+		if err := tx.Model(&model.Material{}).
+			Where("module_id = ? AND id <> ?", targetModuleID, material.ID).
+			Scopes(orderByPosition).
+			Pluck("id", &targetIDs).Error; err != nil {
+			return err
+		}
+
+		if position <= 0 || position > len(targetIDs)+1 {
+			position = len(targetIDs) + 1
+		}
+		ordered := make([]uint64, 0, len(targetIDs)+1)
+		ordered = append(ordered, targetIDs[:position-1]...)
+		ordered = append(ordered, material.ID)
+		ordered = append(ordered, targetIDs[position-1:]...)
+
+		if err := tx.Model(&model.Material{}).Where("id = ?", material.ID).Update("module_id", targetModuleID).Error; err != nil {
+			return err
+		}
+		if err := applyOrder(tx, &model.Material{}, "module_id", targetModuleID, ordered); err != nil {
+			return err
+		}
+
+		if sourceModuleID != targetModuleID {
+			var sourceIDs []uint64
+			if err := tx.Model(&model.Material{}).
+				Where("module_id = ?", sourceModuleID).
+				Scopes(orderByPosition).
+				Pluck("id", &sourceIDs).Error; err != nil {
+				return err
+			}
+			if err := applyOrder(tx, &model.Material{}, "module_id", sourceModuleID, sourceIDs); err != nil {
+				return err
+			}
+		}
+
+		material.ModuleID = targetModuleID
+		material.Order = position
+		return nil
+	})
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+)
+
+func CreatePasswordResetToken(token *model.PasswordResetToken) error {
+	return database.DB.Create(token).Error
+}
+
+func FindPasswordResetTokenByHash(hash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := database.DB.Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+// DeleteUnusedPasswordResetTokens membuang token reset lama yang belum dipakai
+// agar hanya tautan terbaru yang berlaku.
+func DeleteUnusedPasswordResetTokens(userID uint64) error {
+	return database.DB.Where("user_id = ? AND used_at IS NULL", userID).Delete(&model.PasswordResetToken{}).Error
+}
+
+func MarkPasswordResetTokenUsed(id uint64) (bool, error) {
+	result := database.DB.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExportedSubmission struct {
+	ID              uint64    `json:"id"`
+	AssignmentID    uint64    `json:"assignment_id"`
+	AssignmentTitle string    `json:"assignment_title"`
+	CourseID        uint64    `json:"course_id"`
+	TextAnswer      string    `json:"text_answer"`
+	FileURL         string    `json:"file_url"`
+	VoiceNoteURL    string    `json:"voice_note_url"`
+	Grade           float64   `json:"grade"`
+	Feedback        string    `json:"feedback"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+}
+
+type ExportedCompletion struct {
+	MaterialID    uint64    `json:"material_id"`
+	MaterialTitle string    `json:"material_title"`
+	Completed     bool      `json:"completed"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func GetSubmissionsForExport(studentID uint64) ([]ExportedSubmission, error) {
+	var results []ExportedSubmission
+	err := database.DB.Table("submissions").
+		Select("submissions.id, submissions.assignment_id, assignments.title as assignment_title, assignments.course_id, submissions.text_answer, submissions.file_url, submissions.voice_note_url, submissions.grade, submissions.feedback, submissions.submitted_at").
+		Joins("JOIN assignments ON submissions.assignment_id = assignments.id").
+		Where("submissions.student_id = ?", studentID).
+		Order("submissions.submitted_at asc").
+		Scan(&results).Error
+	return results, err
+}
+
+func GetCompletionsForExport(userID uint64) ([]ExportedCompletion, error) {
+	var results []ExportedCompletion
+	err := database.DB.Table("material_completions").
+		Select("material_completions.material_id, materials.title as material_title, material_completions.completed, material_completions.created_at, material_completions.updated_at").
+		Joins("JOIN materials ON material_completions.material_id = materials.id").
+		Where("material_completions.user_id = ?", userID).
+		Order("material_completions.created_at asc").
+		Scan(&results).Error
+	return results, err
+}
+
+func GetActivitiesByUserID(userID uint64) ([]model.Activity, error) {
+	var activities []model.Activity
+	err := database.DB.Where("user_id = ?", userID).Order("created_at asc").Find(&activities).Error
+	return activities, err
+}
+
+func GetLoginAttemptsByUserID(userID uint64) ([]model.LoginAttempt, error) {
+	var attempts []model.LoginAttempt
+	err := database.DB.Where("user_id = ?", userID).Order("created_at asc").Find(&attempts).Error
+	return attempts, err
+}
+
+func CountCoursesOwnedBy(userID uint64) (int64, error) {
+	var count int64
+	err := database.DB.Model(&model.Course{}).Where("teacher_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func CreateAccountDeletionRequest(request *model.AccountDeletionRequest) error {
+	return database.DB.Create(request).Error
+}
+
+func FindAccountDeletionRequestByHash(hash string) (*model.AccountDeletionRequest, error) {
+	var request model.AccountDeletionRequest
+	err := database.DB.Where("token_hash = ?", hash).First(&request).Error
+	return &request, err
+}
+
+func FindPendingAccountDeletionRequest(userID uint64) (*model.AccountDeletionRequest, error) {
+	var request model.AccountDeletionRequest
+	err := database.DB.Where("user_id = ? AND confirmed_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at desc").
+		First(&request).Error
+	return &request, err
+}
+
+func DeletePendingAccountDeletionRequests(userID uint64) error {
+	return database.DB.Where("user_id = ? AND confirmed_at IS NULL", userID).Delete(&model.AccountDeletionRequest{}).Error
+}
+
+// AnonymizeUser menghapus data pribadi user tanpa menghapus barisnya, sehingga nilai,
+// penyelesaian materi dan statistik kelas tetap utuh tetapi tidak lagi dapat ditautkan
+// ke orang tersebut (baris user sendiri menjadi placeholder "Pengguna Terhapus").
+//
+// Dihapus: profil aksesibilitas, sesi, identitas SSO, pertemanan, riwayat login, catatan,
+// bookmark, progres materi, status baca pengumuman, mention, permintaan bergabung.
+// Dikosongkan (baris tetap ada): isi submission, isi thread/balasan diskusi beserta voice note,
+// email undangan kelas, nama di deskripsi aktivitas.
+// Sengaja dipertahankan: nilai submission dan penyelesaian materi (rekap kelas), struktur thread
+// diskusi (agar balasan user lain tetap terbaca) dan audit log (tanpa data pribadi).
+// Mengembalikan URL file upload milik submission dan voice note agar service bisa menghapus
+// filenya setelah transaksi berhasil.
+func AnonymizeUser(userID uint64, placeholderName string, placeholderEmail string, unusablePassword string) ([]string, error) {
+	var uploadedFiles []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		// Nama user tertulis di deskripsi aktivitas ("<nama> mengumpulkan tugas: ...")
+		if user.Name != "" {
+			if err := tx.Model(&model.Activity{}).
+				Where("user_id = ?", userID).
+				Update("description", gorm.Expr("REPLACE(description, ?, ?)", user.Name, placeholderName)).Error; err != nil {
+				return err
+			}
+		}
+
+		// File upload dikumpulkan dulu agar bisa dihapus dari disk setelah commit
+		for _, source := range []struct {
+			model  interface{}
+			column string
+			owner  string
+		}{
+			{&model.Submission{}, "file_url", "student_id"},
+			{&model.Submission{}, "voice_note_url", "student_id"},
+			{&model.DiscussionReply{}, "voice_note_url", "author_id"},
+		} {
+			var files []string
+			if err := tx.Model(source.model).
+				Where(source.owner+" = ? AND "+source.column+" <> ''", userID).
+				Pluck(source.column, &files).Error; err != nil {
+				return err
+			}
+			uploadedFiles = append(uploadedFiles, files...)
+		}
+
+		// Isi jawaban adalah karya pribadi; nilai dipertahankan untuk rekap kelas
+		if err := tx.Model(&model.Submission{}).
+			Where("student_id = ?", userID).
+			Updates(map[string]interface{}{
+				"text_answer":    "",
+				"file_url":       "",
+				"voice_note_url": "",
+			}).Error; err != nil {
+			return err
+		}
+
+		// Thread dan balasan tetap ada agar diskusi user lain tidak rusak, tetapi isinya dikosongkan
+		if err := tx.Model(&model.DiscussionThread{}).
+			Where("author_id = ?", userID).
+			Updates(map[string]interface{}{"title": "[konten dihapus]", "body": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.DiscussionReply{}).
+			Where("author_id = ?", userID).
+			Updates(map[string]interface{}{
+				"body":               "",
+				"voice_note_url":     "",
+				"voice_duration_sec": 0,
+				"transcript":         "",
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR mentioned_by_id = ?", userID, userID).Delete(&model.DiscussionMention{}).Error; err != nil {
+			return err
+		}
+
+		// Undangan menyimpan email; undangan yang belum dipakai tidak bisa diterima lagi
+		if err := tx.Model(&model.CourseInvitation{}).
+			Where("LOWER(email) = LOWER(?) OR accepted_by_id = ?", user.Email, userID).
+			Updates(map[string]interface{}{"email": placeholderEmail, "token_id": ""}).Error; err != nil {
+			return err
+		}
+
+		personal := []interface{}{
+			&model.AccessibilityProfile{},
+			&model.UserSession{},
+			&model.PasswordResetToken{},
+			&model.TwoFactorRecoveryCode{},
+			&model.UserIdentity{},
+			&model.CourseStaff{},
+			&model.LoginAttempt{},
+			&model.MaterialAnnotation{},
+			&model.MaterialBookmark{},
+			&model.MaterialProgress{},
+			&model.AnnouncementRead{},
+			&model.EnrollmentRequest{},
+		}
+		for _, m := range personal {
+			if err := tx.Where("user_id = ?", userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("requester_id = ? OR addressee_id = ?", userID, userID).Delete(&model.Friendship{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM course_students WHERE user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.User{}).Where("created_by_id = ?", userID).Update("created_by_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"name":                    placeholderName,
+			"email":                   placeholderEmail,
+			"password":                unusablePassword,
+			"avatar":                  "",
+			"created_by_id":           nil,
+			"is_verified":             false,
+			"verification_token":      "",
+			"verification_expires_at": nil,
+			"verification_sent_at":    nil,
+			"must_change_password":    false,
+			"two_factor_enabled":      false,
+			"two_factor_secret":       "",
+			"two_factor_last_step":    0,
+			"deactivated_at":          now,
+			"anonymized_at":           now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFiles, nil
+}
+
+func UpdateAccountDeletionRequest(request *model.AccountDeletionRequest) error {
+	return database.DB.Save(request).Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindMaterialProgress mengembalikan gorm.ErrRecordNotFound jika user belum pernah mengirim heartbeat.
+func FindMaterialProgress(userID, materialID uint64) (*model.MaterialProgress, error) {
+	var progress model.MaterialProgress
+	err := database.DB.Where("user_id = ? AND material_id = ?", userID, materialID).First(&progress).Error
+	return &progress, err
+}
+
+// SaveMaterialProgress menyimpan progres sebagai upsert pada (user_id, material_id) sehingga
+// heartbeat pertama yang datang bersamaan (mis. dua tab) tidak gagal karena baris ganda.
+func SaveMaterialProgress(progress *model.MaterialProgress) error {
+	progress.ID = 0
+	return database.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "material_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"time_spent_sec",
+			"last_position_sec", "last_page", "last_scroll_pct",
+			"max_position_sec", "max_page", "max_scroll_pct",
+			"progress_percent", "auto_completed_at", "last_heartbeat_at",
+			"updated_at",
+		}),
+	}).Create(progress).Error
+}
+
+// MarkMaterialCompleted menandai materi selesai (tanpa toggle). Mengembalikan true jika sebelumnya
+// belum selesai.
+func MarkMaterialCompleted(userID, materialID uint64) (bool, error) {
+	var changed bool
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var completion model.MaterialCompletion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND material_id = ?", userID, materialID).
+			First(&completion).Error
+		if err == gorm.ErrRecordNotFound {
+			changed = true
+			return tx.Create(&model.MaterialCompletion{UserID: userID, MaterialID: materialID, Completed: true}).Error
+		} else if err != nil {
+			return err
+		}
+		if completion.Completed {
+			return nil
+		}
+		changed = true
+		completion.Completed = true
+		return tx.Save(&completion).Error
+	})
+	return changed, err
+}
+
+func GetUserMaterialProgress(userID uint64) ([]model.MaterialProgress, error) {
+	var progress []model.MaterialProgress
+	err := database.DB.Where("user_id = ?", userID).Order("created_at asc").Find(&progress).Error
+	return progress, err
+}
+
+// partialMaterialProgress menjumlahkan progres parsial (0-1 per materi) dari materi yang belum
+// selesai di kelas. studentID = 0 berarti semua mahasiswa (untuk dashboard pengajar).
+func partialMaterialProgress(courseID, studentID uint64) float64 {
+	var total float64
+	query := database.DB.Table("material_progresses").
+		Select("COALESCE(SUM(material_progresses.progress_percent), 0) / 100").
+		Joins("JOIN materials ON material_progresses.material_id = materials.id").
+		Joins("JOIN modules ON materials.module_id = modules.id").
+		Joins("LEFT JOIN material_completions ON material_completions.material_id = material_progresses.material_id AND material_completions.user_id = material_progresses.user_id AND material_completions.completed = ?", true).
+		Where("modules.course_id = ? AND material_completions.id IS NULL", courseID)
+	if studentID != 0 {
+		query = query.Where("material_progresses.user_id = ?", studentID)
+	}
+	query.Scan(&total)
+	return total
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+)
+
+func CreateSession(session *model.UserSession) error {
+	return database.DB.Create(session).Error
+}
+
+func FindSessionByID(id uint64) (*model.UserSession, error) {
+	var session model.UserSession
+	err := database.DB.First(&session, id).Error
+	return &session, err
+}
+
+func IsSessionActive(id uint64) bool {
+	var count int64
+	database.DB.Model(&model.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&count)
+	return count > 0
+}
+
+func RevokeSession(id uint64) error {
+	return database.DB.Model(&model.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func RevokeSessionsByUserID(userID uint64) error {
+	return database.DB.Model(&model.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func CreateRefreshToken(token *model.RefreshToken) error {
+	return database.DB.Create(token).Error
+}
+
+func FindRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := database.DB.Preload("Session").Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+// MarkRefreshTokenUsed menandai token sebagai terpakai hanya jika belum pernah dipakai.
+// Mengembalikan false bila token sudah dipakai oleh request lain (indikasi token dicuri).
+func MarkRefreshTokenUsed(id uint64) (bool, error) {
+	result := database.DB.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
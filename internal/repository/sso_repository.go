@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func CreateSSOLoginState(state *model.SSOLoginState) error {
+	return database.DB.Create(state).Error
+}
+
+// ConsumeSSOLoginState mengambil lalu menghapus state agar tidak bisa dipakai ulang.
+func ConsumeSSOLoginState(hash string) (*model.SSOLoginState, error) {
+	var state model.SSOLoginState
+	if err := database.DB.Where("state_hash = ?", hash).First(&state).Error; err != nil {
+		return nil, err
+	}
+
+	result := database.DB.Delete(&model.SSOLoginState{}, state.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &state, nil
+}
+
+func DeleteExpiredSSOLoginStates() error {
+	return database.DB.Where("expires_at < ?", time.Now()).Delete(&model.SSOLoginState{}).Error
+}
+
+func FindUserIdentity(provider string, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := database.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	return &identity, err
+}
+
+func CreateUserIdentity(identity *model.UserIdentity) error {
+	return database.DB.Create(identity).Error
+}
+
+func TouchUserIdentity(id uint64) error {
+	return database.DB.Model(&model.UserIdentity{}).Where("id = ?", id).Update("last_login_at", time.Now()).Error
+}
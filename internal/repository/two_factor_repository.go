@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReplaceRecoveryCodes menghapus seluruh kode cadangan lama user lalu menyimpan yang baru.
+func ReplaceRecoveryCodes(userID uint64, codes []model.TwoFactorRecoveryCode) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func DeleteRecoveryCodesByUserID(userID uint64) error {
+	return database.DB.Where("user_id = ?", userID).Delete(&model.TwoFactorRecoveryCode{}).Error
+}
+
+func CountUnusedRecoveryCodes(userID uint64) (int64, error) {
+	var count int64
+	err := database.DB.Model(&model.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// UseRecoveryCode menandai kode cadangan terpakai. Mengembalikan false jika kode
+// tidak ada atau sudah pernah dipakai.
+func UseRecoveryCode(userID uint64, hash string) (bool, error) {
+	result := database.DB.Model(&model.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimTOTPStep mencatat time-step TOTP yang baru diterima secara atomik. Mengembalikan false jika
+// step yang sama atau lebih baru sudah dipakai (termasuk oleh request lain yang berjalan bersamaan).
+func ClaimTOTPStep(userID uint64, step int64) (bool, error) {
+	result := database.DB.Model(&model.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
@@ -15,6 +15,15 @@ func FindUserByEmail(email string) (*model.User, error) {
 	return &user, err
 }
 
+// FindUserByEmailIgnoreCase mencocokkan email tanpa membedakan huruf besar/kecil, untuk email dari
+// sumber luar (undangan, penyedia SSO) yang sudah dinormalkan ke huruf kecil sementara email akun
+// tersimpan sesuai ketikan saat registrasi.
+func FindUserByEmailIgnoreCase(email string) (*model.User, error) {
+	var user model.User
+	err := database.DB.Preload("Accessibility").Where("LOWER(email) = LOWER(?)", email).Order("id asc").First(&user).Error
+	return &user, err
+}
+
 func FindUserByID(id uint64) (*model.User, error) {
 	var user model.User
 	err := database.DB.Preload("Accessibility").First(&user, id).Error
@@ -34,3 +43,46 @@ func UpdateUser(user *model.User) error {
 func DeleteUser(id uint64) error {
 	return database.DB.Delete(&model.User{}, id).Error
 }
+
+// UpdateUserFields memperbarui kolom tertentu saja tanpa menyentuh relasi (Accessibility, dsb.).
+func UpdateUserFields(id uint64, fields map[string]interface{}) error {
+	return database.DB.Model(&model.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func SearchUsers(search string, role string, status string, limit int, offset int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := database.DB.Model(&model.User{})
+
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if role != "" && role != "all" {
+		query = query.Where("role = ?", role)
+	}
+
+	switch status {
+	case "active":
+		query = query.Where("deactivated_at IS NULL")
+	case "deactivated":
+		query = query.Where("deactivated_at IS NOT NULL")
+	case "unverified":
+		query = query.Where("is_verified = ?", false)
+	case "locked":
+		query = query.Where("locked_until > NOW()")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+
+	return users, total, err
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"ramah-disabilitas-be/internal/handler"
 	"ramah-disabilitas-be/internal/middleware"
+	"ramah-disabilitas-be/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,7 +13,8 @@ func SetupRouter() *gin.Engine {
 	r.Use(middleware.CORSMiddleware())
 
 	// Serve static files from storage directory
-	r.Static("/storage", "./storage")
+	storage := r.Group("/storage", middleware.StorageMiddleware())
+	storage.Static("/", "./storage")
 
 	r.GET("/", func(c *gin.Context) {
 		c.String(200, "Halo! Aplikasi Go berhasil jalan di Koyeb.")
@@ -24,7 +26,18 @@ func SetupRouter() *gin.Engine {
 		{
 			auth.POST("/register", handler.Register)
 			auth.POST("/login", handler.Login)
+			auth.POST("/login/2fa", handler.LoginTwoFactor)
+			auth.POST("/refresh", handler.RefreshToken)
 			auth.GET("/verify-email", handler.VerifyEmail)
+			auth.POST("/resend-verification", handler.ResendVerification)
+			auth.POST("/forgot-password", handler.ForgotPassword)
+			auth.POST("/reset-password", handler.ResetPassword)
+			auth.POST("/account-deletion/confirm", handler.ConfirmAccountDeletion)
+			auth.GET("/invitations", handler.GetInvitationInfo)
+			auth.POST("/invitations/register", handler.RegisterWithInvitation)
+			auth.GET("/sso/providers", handler.ListSSOProviders)
+			auth.GET("/sso/:provider/authorize", handler.StartSSOLogin)
+			auth.POST("/sso/:provider/callback", handler.SSOCallback)
 		}
 
 		protected := api.Group("/")
@@ -33,6 +46,12 @@ func SetupRouter() *gin.Engine {
 			protected.POST("/upload", handler.UploadFile)
 			protected.GET("/auth/me", handler.GetMe)
 			protected.POST("/auth/logout", handler.Logout)
+			protected.POST("/auth/change-password", handler.ChangePassword)
+			protected.GET("/auth/2fa", handler.GetTwoFactorStatus)
+			protected.POST("/auth/2fa/setup", handler.SetupTwoFactor)
+			protected.POST("/auth/2fa/enable", handler.EnableTwoFactor)
+			protected.POST("/auth/2fa/disable", handler.DisableTwoFactor)
+			protected.POST("/auth/2fa/recovery-codes", handler.RegenerateRecoveryCodes)
 
 			admin := protected.Group("/admin")
 			admin.Use(middleware.AdminMiddleware())
@@ -40,24 +59,67 @@ func SetupRouter() *gin.Engine {
 				admin.GET("/health", func(c *gin.Context) {
 					c.JSON(200, gin.H{"status": "ok", "role": "admin"})
 				})
+				admin.GET("/users", handler.ListUsers)
+				admin.GET("/users/:id", handler.GetUserByAdmin)
+				admin.PUT("/users/:id/role", handler.ChangeUserRole)
+				admin.POST("/users/:id/verify", handler.VerifyUserByAdmin)
+				admin.POST("/users/:id/deactivate", handler.DeactivateUser)
+				admin.POST("/users/:id/activate", handler.ActivateUser)
+				admin.POST("/users/:id/reset-password", handler.ResetPasswordByAdmin)
+				admin.GET("/users/:id/courses", handler.GetUserCourses)
+				admin.GET("/users/:id/accessibility", handler.GetUserAccessibilityByAdmin)
+				admin.POST("/users/:id/sessions/revoke", handler.RevokeUserSessions)
+				admin.POST("/users/:id/2fa/reset", handler.ResetTwoFactorByAdmin)
+				admin.POST("/users/:id/unlock", handler.UnlockUser)
+				admin.GET("/audit-logs", handler.ListAuditLogs)
 			}
 
 			protected.POST("/user/accessibility", handler.UpdateAccessibility)
+			protected.GET("/user/export", handler.ExportUserData)
+			protected.POST("/user/deletion-request", handler.RequestAccountDeletion)
+			protected.DELETE("/user/deletion-request", handler.CancelAccountDeletion)
 			protected.POST("/courses/join", handler.JoinCourse)
 			protected.GET("/courses/joined", handler.GetMyJoinedCourses)
 			protected.GET("/courses/assignments", handler.GetMyAssignments)
-			protected.GET("/courses/:id", handler.GetStudentCourseDetail)
-			protected.GET("/courses/:id/members", handler.GetCourseMembers)
-			protected.GET("/courses/:id/assignments", handler.GetStudentCourseAssignments)
-			protected.GET("/assignments/:id", handler.GetAssignmentDetail)
-			protected.POST("/assignments/:id/submit", handler.SubmitAssignment)
-			protected.GET("/materials/:id", handler.GetMaterialDetail)
-			protected.POST("/materials/:id/complete", handler.ToggleMaterialCompletion)
-			protected.POST("/materials/:id/summary", handler.GenerateMaterialSummary)
-			protected.POST("/materials/:id/summary/save", handler.SaveMaterialSummary)
-			protected.POST("/materials/:id/chat", handler.ChatWithMaterial)
-			protected.POST("/materials/:id/quiz", handler.GenerateQuizFromMaterial)
-			protected.POST("/materials/:id/flashcards", handler.GenerateFlashcardsFromMaterial)
+			protected.GET("/courses/enrollment-requests", handler.GetMyEnrollmentRequests)
+			protected.POST("/courses/invitations/accept", handler.AcceptCourseInvitation)
+			protected.GET("/courses/:id", middleware.RequirePermission(service.PermCourseView, "id"), handler.GetStudentCourseDetail)
+			protected.GET("/courses/:id/members", middleware.RequirePermission(service.PermCourseView, "id"), handler.GetCourseMembers)
+			protected.GET("/courses/:id/assignments", middleware.RequirePermission(service.PermCourseView, "id"), handler.GetStudentCourseAssignments)
+			protected.GET("/courses/:id/announcements", middleware.RequirePermission(service.PermCourseView, "id"), handler.GetStudentCourseAnnouncements)
+			protected.POST("/courses/:id/leave", handler.LeaveCourse)
+			protected.POST("/announcements/:id/read", middleware.RequirePermission(service.PermAnnouncementView, "id"), handler.MarkAnnouncementRead)
+			protected.GET("/courses/:id/discussions", middleware.RequirePermission(service.PermCourseView, "id"), handler.GetCourseThreads)
+			protected.POST("/courses/:id/discussions", middleware.RequirePermission(service.PermCourseView, "id"), handler.CreateCourseThread)
+			protected.GET("/courses/:id/notes/export", middleware.RequirePermission(service.PermCourseView, "id"), handler.ExportCourseNotes)
+			protected.PUT("/annotations/:id", handler.UpdateAnnotation)
+			protected.DELETE("/annotations/:id", handler.DeleteAnnotation)
+			protected.GET("/bookmarks", handler.GetMyBookmarks)
+			protected.DELETE("/bookmarks/:id", handler.DeleteBookmark)
+			protected.GET("/discussions/mentions", handler.GetMyMentions)
+			protected.POST("/discussions/mentions/read", handler.MarkMentionsRead)
+			protected.GET("/discussions/:id", middleware.RequirePermission(service.PermThreadView, "id"), handler.GetThreadDetail)
+			protected.POST("/discussions/:id/replies", middleware.RequirePermission(service.PermThreadReply, "id"), handler.CreateThreadReply)
+			protected.GET("/assignments/:id", middleware.RequirePermission(service.PermAssignmentView, "id"), handler.GetAssignmentDetail)
+			protected.POST("/assignments/:id/submit", middleware.RequirePermission(service.PermAssignmentSubmit, "id"), handler.SubmitAssignment)
+
+			material := protected.Group("/materials/:id")
+			material.Use(middleware.RequirePermission(service.PermMaterialView, "id"))
+			{
+				material.GET("", handler.GetMaterialDetail)
+				material.POST("/complete", middleware.RequirePermission(service.PermMaterialComplete, "id"), handler.ToggleMaterialCompletion)
+				material.POST("/progress", middleware.RequirePermission(service.PermMaterialComplete, "id"), handler.RecordMaterialProgress)
+				material.POST("/summary", handler.GenerateMaterialSummary)
+				material.POST("/summary/save", handler.SaveMaterialSummary)
+				material.POST("/chat", handler.ChatWithMaterial)
+				material.POST("/quiz", handler.GenerateQuizFromMaterial)
+				material.POST("/flashcards", handler.GenerateFlashcardsFromMaterial)
+				material.GET("/discussions", handler.GetMaterialThreads)
+				material.POST("/discussions", handler.CreateMaterialThread)
+				material.GET("/annotations", handler.GetMaterialAnnotations)
+				material.POST("/annotations", handler.CreateAnnotation)
+				material.POST("/bookmarks", handler.CreateBookmark)
+			}
 
 			lecturer := protected.Group("/lecturer")
 			lecturer.Use(middleware.LecturerMiddleware())
@@ -72,25 +134,65 @@ func SetupRouter() *gin.Engine {
 				lecturer.POST("/students", handler.CreateStudentByLecturer)
 				lecturer.POST("/students/import", handler.ImportStudents)
 				lecturer.POST("/courses", handler.CreateCourse)
-				lecturer.POST("/courses/:id/students", handler.CreateStudentAndEnroll)
-				lecturer.PUT("/students/:id", handler.UpdateStudentByLecturer)
-				lecturer.DELETE("/students/:id", handler.DeleteStudentByLecturer)
-				lecturer.GET("/courses/:id/students", handler.GetCourseStudents)
-				lecturer.POST("/courses/:id/students/import", handler.ImportStudentsToCourse)
+				lecturer.POST("/courses/:id/students", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.CreateStudentAndEnroll)
+				lecturer.PUT("/students/:id", middleware.RequirePermission(service.PermStudentManage, "id"), handler.UpdateStudentByLecturer)
+				lecturer.DELETE("/students/:id", middleware.RequirePermission(service.PermStudentManage, "id"), handler.DeleteStudentByLecturer)
+				lecturer.GET("/courses/:id/students", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.GetCourseStudents)
+				lecturer.POST("/courses/:id/students/import", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.ImportStudentsToCourse)
+				lecturer.PUT("/courses/:id/enrollment", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.UpdateEnrollmentSettings)
+				lecturer.POST("/courses/:id/class-code/regenerate", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.RegenerateClassCode)
+				lecturer.GET("/courses/:id/enrollment-requests", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.GetEnrollmentRequests)
+				lecturer.POST("/courses/:id/enrollment-requests/:requestId/approve", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.ApproveEnrollmentRequest)
+				lecturer.POST("/courses/:id/enrollment-requests/:requestId/reject", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.RejectEnrollmentRequest)
+				lecturer.POST("/courses/:id/invitations", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.InviteStudentsToCourse)
+				lecturer.GET("/courses/:id/invitations", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.GetCourseInvitations)
+				lecturer.DELETE("/courses/:id/invitations/:invitationId", middleware.RequirePermission(service.PermCourseManageStudents, "id"), handler.RevokeCourseInvitation)
+				lecturer.GET("/courses/:id/staff", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.GetCourseStaff)
+				lecturer.GET("/courses/:id/audit-logs", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.ListCourseAuditLogs)
+				lecturer.POST("/courses/:id/preview", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.StartStudentPreview)
+				lecturer.POST("/courses/:id/staff", middleware.RequirePermission(service.PermCourseManageStaff, "id"), handler.AddCourseStaff)
+				lecturer.PUT("/courses/:id/staff/:userId", middleware.RequirePermission(service.PermCourseManageStaff, "id"), handler.UpdateCourseStaff)
+				lecturer.DELETE("/courses/:id/staff/:userId", middleware.RequirePermission(service.PermCourseManageStaff, "id"), handler.RemoveCourseStaff)
 				lecturer.GET("/courses", handler.GetMyCourses)
-				lecturer.GET("/courses/:id", handler.GetCourseDetail)
-				lecturer.PUT("/courses/:id", handler.UpdateCourse)
-				lecturer.DELETE("/courses/:id", handler.DeleteCourse)
-				lecturer.DELETE("/modules/:id", handler.DeleteModule)
-				lecturer.POST("/modules/:id/materials", handler.CreateMaterial)
-				lecturer.DELETE("/materials/:id", handler.DeleteMaterial)
-				lecturer.PUT("/materials/:id", handler.UpdateMaterial)
-				lecturer.POST("/courses/:id/assignments", handler.CreateAssignment)
-				lecturer.GET("/courses/:id/assignments", handler.GetAssignments)
-				lecturer.PUT("/assignments/:id", handler.UpdateAssignment)
-				lecturer.DELETE("/assignments/:id", handler.DeleteAssignment)
-				lecturer.POST("/submissions/:id/grade", handler.GradeSubmission)
-				lecturer.GET("/assignments/:id/submissions", handler.GetAssignmentSubmissions)
+				lecturer.GET("/courses/:id", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.GetCourseDetail)
+				lecturer.PUT("/courses/:id", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.UpdateCourse)
+				lecturer.PUT("/courses/:id/status", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.ChangeCourseStatus)
+				lecturer.DELETE("/courses/:id", middleware.RequirePermission(service.PermCourseDelete, "id"), handler.DeleteCourse)
+				lecturer.POST("/courses/:id/clone", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.CloneCourse)
+				lecturer.POST("/courses/:id/templates", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.PublishCourseTemplate)
+				lecturer.GET("/courses/:id/export/cartridge", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.ExportCourseCartridge)
+				lecturer.POST("/courses/import", handler.ImportCourseCartridge)
+				lecturer.GET("/templates", handler.GetCourseTemplates)
+				lecturer.GET("/templates/:id", handler.GetCourseTemplateDetail)
+				lecturer.DELETE("/templates/:id", handler.DeleteCourseTemplate)
+				lecturer.POST("/templates/:id/instantiate", handler.InstantiateCourseTemplate)
+				lecturer.POST("/courses/:id/modules", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.CreateModule)
+				lecturer.PUT("/courses/:id/modules/order", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.ReorderModules)
+				lecturer.PUT("/modules/:id", middleware.RequirePermission(service.PermModuleEdit, "id"), handler.UpdateModule)
+				lecturer.PUT("/modules/:id/materials/order", middleware.RequirePermission(service.PermModuleEdit, "id"), handler.ReorderMaterials)
+				lecturer.DELETE("/modules/:id", middleware.RequirePermission(service.PermModuleEdit, "id"), handler.DeleteModule)
+				lecturer.POST("/materials/:id/move", middleware.RequirePermission(service.PermMaterialEdit, "id"), handler.MoveMaterial)
+				lecturer.GET("/materials/:id/versions", middleware.RequirePermission(service.PermMaterialTeach, "id"), handler.GetMaterialVersions)
+				lecturer.GET("/materials/:id/versions/diff", middleware.RequirePermission(service.PermMaterialTeach, "id"), handler.DiffMaterialVersions)
+				lecturer.GET("/materials/:id/versions/:version", middleware.RequirePermission(service.PermMaterialTeach, "id"), handler.GetMaterialVersion)
+				lecturer.POST("/materials/:id/versions/:version/rollback", middleware.RequirePermission(service.PermMaterialEdit, "id"), handler.RollbackMaterial)
+				lecturer.POST("/modules/:id/materials", middleware.RequirePermission(service.PermModuleEdit, "id"), handler.CreateMaterial)
+				lecturer.DELETE("/materials/:id", middleware.RequirePermission(service.PermMaterialEdit, "id"), handler.DeleteMaterial)
+				lecturer.PUT("/materials/:id", middleware.RequirePermission(service.PermMaterialEdit, "id"), handler.UpdateMaterial)
+				lecturer.POST("/courses/:id/announcements", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.CreateAnnouncement)
+				lecturer.GET("/courses/:id/announcements", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.GetLecturerCourseAnnouncements)
+				lecturer.PUT("/announcements/:id", middleware.RequirePermission(service.PermAnnouncementEdit, "id"), handler.UpdateAnnouncement)
+				lecturer.DELETE("/announcements/:id", middleware.RequirePermission(service.PermAnnouncementEdit, "id"), handler.DeleteAnnouncement)
+				lecturer.PUT("/discussions/:id/lock", middleware.RequirePermission(service.PermThreadModerate, "id"), handler.LockThread)
+				lecturer.PUT("/discussions/:id/hide", middleware.RequirePermission(service.PermThreadModerate, "id"), handler.HideThread)
+				lecturer.PUT("/discussion-replies/:id/hide", middleware.RequirePermission(service.PermReplyModerate, "id"), handler.HideThreadReply)
+				lecturer.PUT("/discussion-replies/:id/answer", middleware.RequirePermission(service.PermReplyModerate, "id"), handler.MarkReplyAnswer)
+				lecturer.POST("/courses/:id/assignments", middleware.RequirePermission(service.PermCourseEdit, "id"), handler.CreateAssignment)
+				lecturer.GET("/courses/:id/assignments", middleware.RequirePermission(service.PermCourseTeach, "id"), handler.GetAssignments)
+				lecturer.PUT("/assignments/:id", middleware.RequirePermission(service.PermAssignmentEdit, "id"), handler.UpdateAssignment)
+				lecturer.DELETE("/assignments/:id", middleware.RequirePermission(service.PermAssignmentEdit, "id"), handler.DeleteAssignment)
+				lecturer.POST("/submissions/:id/grade", middleware.RequirePermission(service.PermSubmissionGrade, "id"), handler.GradeSubmission)
+				lecturer.GET("/assignments/:id/submissions", middleware.RequirePermission(service.PermAssignmentGrade, "id"), handler.GetAssignmentSubmissions)
 			}
 		}
 	}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"time"
+)
+
+type UserListResponse struct {
+	Users      []model.User   `json:"users"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+type UserCoursesResponse struct {
+	Teaching []model.Course `json:"teaching"`
+	Joined   []model.Course `json:"joined"`
+}
+
+type ChangeRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type AdminResetPasswordInput struct {
+	// Jika kosong, tautan reset password dikirim ke email user.
+	Password string `json:"password" binding:"omitempty,min=6"`
+}
+
+func ListUsers(search, role, status string, page, limit int) (*UserListResponse, error) {
+	offset := (page - 1) * limit
+	users, total, err := repository.SearchUsers(search, role, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if users == nil {
+		users = []model.User{}
+	}
+
+	totalPage := int((total + int64(limit) - 1) / int64(limit))
+
+	return &UserListResponse{
+		Users: users,
+		Pagination: PaginationMeta{
+			CurrentPage: page,
+			TotalPage:   totalPage,
+			TotalItems:  total,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+func GetUserByAdmin(userID uint64) (*model.User, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	return user, nil
+}
+
+// findManageableUser mengambil user yang akan diubah admin. Akun yang sudah dianonimkan tidak
+// bisa dipulihkan, diberi role, diverifikasi, atau diberi password baru.
+func findManageableUser(userID uint64) (*model.User, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if user.AnonymizedAt != nil {
+		return nil, errors.New("akun sudah dihapus")
+	}
+	return user, nil
+}
+
+func ChangeUserRole(userID uint64, input ChangeRoleInput, adminID uint64, meta SessionMeta) (*model.User, error) {
+	user, err := findManageableUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var role model.UserRole
+	switch input.Role {
+	case "mahasiswa", "student":
+		role = model.RoleStudent
+	case "dosen", "lecturer":
+		role = model.RoleLecturer
+	case "admin":
+		role = model.RoleAdmin
+	default:
+		return nil, errors.New("role tidak valid (pilih 'student', 'lecturer' atau 'admin')")
+	}
+
+	if userID == adminID && role != model.RoleAdmin {
+		return nil, errors.New("role tidak valid: anda tidak bisa menurunkan role akun sendiri")
+	}
+
+	previousRole := user.Role
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{"role": role}); err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	// Role tersimpan di access token, jadi sesi lama harus dicabut
+	if err := RevokeUserSessions(userID); err != nil {
+		return nil, err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserRoleChange,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"role": previousRole},
+		After:      map[string]interface{}{"role": role},
+	})
+
+	return user, nil
+}
+
+func VerifyUserByAdmin(userID uint64, adminID uint64, meta SessionMeta) error {
+	user, err := findManageableUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{
+		"is_verified":             true,
+		"verification_token":      "",
+		"verification_expires_at": nil,
+	}); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserVerify,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"is_verified": user.IsVerified},
+		After:      map[string]interface{}{"is_verified": true},
+	})
+
+	return nil
+}
+
+func DeactivateUser(userID uint64, adminID uint64, meta SessionMeta) error {
+	if userID == adminID {
+		return errors.New("anda tidak bisa menonaktifkan akun sendiri")
+	}
+
+	if _, err := repository.FindUserByID(userID); err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{"deactivated_at": time.Now()}); err != nil {
+		return err
+	}
+
+	if err := RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserDeactivate,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"active": true},
+		After:      map[string]interface{}{"active": false},
+	})
+
+	return nil
+}
+
+func ActivateUser(userID uint64, adminID uint64, meta SessionMeta) error {
+	if _, err := findManageableUser(userID); err != nil {
+		return err
+	}
+
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{"deactivated_at": nil}); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserActivate,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"active": false},
+		After:      map[string]interface{}{"active": true},
+	})
+
+	return nil
+}
+
+// ResetPasswordByAdmin mengatur password sementara (user wajib menggantinya saat login)
+// atau, jika password kosong, mengirim tautan reset password ke email user.
+func ResetPasswordByAdmin(userID uint64, input AdminResetPasswordInput, adminID uint64, meta SessionMeta) error {
+	user, err := findManageableUser(userID)
+	if err != nil {
+		return err
+	}
+
+	method := "temporary_password"
+	if input.Password == "" {
+		method = "email_link"
+		if err := ForgotPassword(user.Email); err != nil {
+			return err
+		}
+	} else {
+		hashedPassword, err := utils.HashPassword(input.Password)
+		if err != nil {
+			return err
+		}
+
+		if err := repository.UpdateUserFields(userID, map[string]interface{}{
+			"password":              hashedPassword,
+			"must_change_password":  true,
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}); err != nil {
+			return err
+		}
+
+		if err := RevokeUserSessions(userID); err != nil {
+			return err
+		}
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserPasswordReset,
+		TargetType: "user",
+		TargetID:   userID,
+		After:      map[string]interface{}{"method": method},
+	})
+
+	return nil
+}
+
+func UnlockUserByAdmin(userID uint64, adminID uint64, meta SessionMeta) error {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := UnlockUser(userID); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserUnlock,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"failed_login_attempts": user.FailedLoginAttempts, "locked_until": user.LockedUntil},
+		After:      map[string]interface{}{"failed_login_attempts": 0, "locked_until": nil},
+	})
+
+	return nil
+}
+
+func RevokeUserSessionsByAdmin(userID uint64, adminID uint64, meta SessionMeta) error {
+	if _, err := repository.FindUserByID(userID); err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserSessionsRevoke,
+		TargetType: "user",
+		TargetID:   userID,
+	})
+
+	return nil
+}
+
+func GetUserCourses(userID uint64) (*UserCoursesResponse, error) {
+	if _, err := repository.FindUserByID(userID); err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	teaching, err := repository.GetCoursesByTeacherID(userID, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := repository.GetCoursesByStudentID(userID, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	if teaching == nil {
+		teaching = []model.Course{}
+	}
+	if joined == nil {
+		joined = []model.Course{}
+	}
+
+	return &UserCoursesResponse{
+		Teaching: teaching,
+		Joined:   joined,
+	}, nil
+}
+
+func GetUserAccessibilityByAdmin(userID uint64) (*model.AccessibilityProfile, error) {
+	if _, err := repository.FindUserByID(userID); err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	profile, err := repository.FindAccessibilityProfileByUserID(userID)
+	if err != nil {
+		return nil, errors.New("profil aksesibilitas tidak ditemukan")
+	}
+	return profile, nil
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"strings"
+	"time"
+)
+
+var annotationColors = map[string]bool{
+	"yellow": true, "green": true, "blue": true, "pink": true, "orange": true,
+}
+
+const defaultHighlightColor = "yellow"
+
+type AnnotationInput struct {
+	Kind   model.AnnotationKind   `json:"kind" binding:"required,oneof=note highlight"`
+	Anchor model.AnnotationAnchor `json:"anchor"`
+	Quote  string                 `json:"quote"` // Teks yang disorot (PDF); untuk materi teks diambil dari isi materi
+	Note   string                 `json:"note"`
+	Color  string                 `json:"color"`
+}
+
+type BookmarkInput struct {
+	Label  string                 `json:"label" binding:"max=255"`
+	Anchor model.AnnotationAnchor `json:"anchor"`
+}
+
+// MaterialAnnotations adalah seluruh anotasi pribadi user pada satu materi.
+type MaterialAnnotations struct {
+	Annotations []model.MaterialAnnotation `json:"annotations"`
+	Bookmarks   []model.MaterialBookmark   `json:"bookmarks"`
+}
+
+// validateAnchor memeriksa posisi sesuai jenis materi. required berarti posisi wajib ada
+// (sorotan); catatan dan bookmark boleh tanpa posisi. Untuk materi teks, kutipan dikembalikan
+// dari isi materi agar selalu sesuai rentang.
+func validateAnchor(material *model.Material, anchor model.AnnotationAnchor, required bool) (string, error) {
+	for _, v := range []*int{anchor.Page, anchor.StartOffset, anchor.EndOffset, anchor.StartSec, anchor.EndSec} {
+		if v != nil && *v < 0 {
+			return "", errors.New("input anotasi tidak valid: posisi tidak boleh negatif")
+		}
+	}
+	hasOffsets := anchor.StartOffset != nil || anchor.EndOffset != nil
+	if hasOffsets && (anchor.StartOffset == nil || anchor.EndOffset == nil || *anchor.StartOffset >= *anchor.EndOffset) {
+		return "", errors.New("input anotasi tidak valid: rentang karakter harus memiliki awal dan akhir")
+	}
+	hasTime := anchor.StartSec != nil || anchor.EndSec != nil
+
+	switch material.Type {
+	case model.TypePDF, model.TypeSlides:
+		if hasTime {
+			return "", errors.New("input anotasi tidak valid: materi " + string(material.Type) + " memakai nomor halaman")
+		}
+		if anchor.Page == nil {
+			if required || hasOffsets {
+				return "", errors.New("input anotasi tidak valid: nomor halaman wajib diisi")
+			}
+			return "", nil
+		}
+		if *anchor.Page < 1 || (material.Media.PageCount > 0 && *anchor.Page > material.Media.PageCount) {
+			return "", errors.New("input anotasi tidak valid: nomor halaman di luar jangkauan")
+		}
+	case model.TypeText:
+		if hasTime || anchor.Page != nil {
+			return "", errors.New("input anotasi tidak valid: materi teks memakai rentang karakter")
+		}
+		if !hasOffsets {
+			if required {
+				return "", errors.New("input anotasi tidak valid: rentang karakter wajib diisi")
+			}
+			return "", nil
+		}
+		content := []rune(material.RawContent)
+		if *anchor.EndOffset > len(content) {
+			return "", errors.New("input anotasi tidak valid: rentang karakter di luar isi materi")
+		}
+		return string(content[*anchor.StartOffset:*anchor.EndOffset]), nil
+	case model.TypeYoutube, model.TypeAudio:
+		if hasOffsets || anchor.Page != nil {
+			return "", errors.New("input anotasi tidak valid: materi " + string(material.Type) + " memakai penanda waktu")
+		}
+		if anchor.StartSec == nil {
+			if required || anchor.EndSec != nil {
+				return "", errors.New("input anotasi tidak valid: penanda waktu wajib diisi")
+			}
+			return "", nil
+		}
+		if anchor.EndSec != nil && *anchor.EndSec < *anchor.StartSec {
+			return "", errors.New("input anotasi tidak valid: akhir penanda waktu sebelum awalnya")
+		}
+		duration := material.Media.DurationSec
+		if duration == 0 {
+			duration = material.DurationMin * 60
+		}
+		end := *anchor.StartSec
+		if anchor.EndSec != nil {
+			end = *anchor.EndSec
+		}
+		if duration > 0 && end > duration {
+			return "", errors.New("input anotasi tidak valid: penanda waktu melebihi durasi materi")
+		}
+	default:
+		if anchor.Page != nil || hasOffsets || hasTime {
+			return "", errors.New("input anotasi tidak valid: materi " + string(material.Type) + " tidak mendukung posisi")
+		}
+		if required {
+			return "", errors.New("input anotasi tidak valid: materi " + string(material.Type) + " tidak dapat disorot")
+		}
+	}
+	return "", nil
+}
+
+// applyAnnotationInput memvalidasi input terhadap materi lalu mengisinya ke annotation.
+func applyAnnotationInput(annotation *model.MaterialAnnotation, material *model.Material, input AnnotationInput) error {
+	highlight := input.Kind == model.AnnotationHighlight
+	quote, err := validateAnchor(material, input.Anchor, highlight)
+	if err != nil {
+		return err
+	}
+	if quote == "" {
+		quote = strings.TrimSpace(input.Quote)
+	}
+
+	note := strings.TrimSpace(input.Note)
+	if !highlight && note == "" {
+		return errors.New("input anotasi tidak valid: isi catatan wajib diisi")
+	}
+
+	color := strings.ToLower(strings.TrimSpace(input.Color))
+	if color == "" && highlight {
+		color = defaultHighlightColor
+	}
+	if color != "" && !annotationColors[color] {
+		return errors.New("input anotasi tidak valid: warna tidak dikenal")
+	}
+
+	annotation.Kind = input.Kind
+	annotation.Anchor = input.Anchor
+	annotation.Quote = quote
+	annotation.Note = note
+	annotation.Color = color
+	return nil
+}
+
+func CreateAnnotation(materialID uint64, input AnnotationInput, userID uint64) (*model.MaterialAnnotation, error) {
+	if err := authorize(userID, PermMaterialView, materialID); err != nil {
+		return nil, err
+	}
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+
+	annotation := &model.MaterialAnnotation{UserID: userID, MaterialID: materialID}
+	if err := applyAnnotationInput(annotation, material, input); err != nil {
+		return nil, err
+	}
+	if err := repository.CreateAnnotation(annotation); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// findOwnAnnotation hanya mengembalikan anotasi milik user; milik user lain dianggap tidak ada.
+func findOwnAnnotation(annotationID, userID uint64) (*model.MaterialAnnotation, error) {
+	annotation, err := repository.FindAnnotationByID(annotationID)
+	if err != nil || annotation.UserID != userID {
+		return nil, errors.New("catatan tidak ditemukan")
+	}
+	return annotation, nil
+}
+
+func UpdateAnnotation(annotationID uint64, input AnnotationInput, userID uint64) (*model.MaterialAnnotation, error) {
+	annotation, err := findOwnAnnotation(annotationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	material, err := repository.GetMaterialByID(annotation.MaterialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+
+	if err := applyAnnotationInput(annotation, material, input); err != nil {
+		return nil, err
+	}
+	if err := repository.UpdateAnnotation(annotation); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+func DeleteAnnotation(annotationID uint64, userID uint64) error {
+	if _, err := findOwnAnnotation(annotationID, userID); err != nil {
+		return err
+	}
+	return repository.DeleteAnnotation(annotationID)
+}
+
+func GetMaterialAnnotations(materialID uint64, userID uint64) (*MaterialAnnotations, error) {
+	if err := authorize(userID, PermMaterialView, materialID); err != nil {
+		return nil, err
+	}
+
+	annotations, err := repository.GetMaterialAnnotations(userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks, err := repository.GetMaterialBookmarks(userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MaterialAnnotations{Annotations: annotations, Bookmarks: bookmarks}
+	if result.Annotations == nil {
+		result.Annotations = []model.MaterialAnnotation{}
+	}
+	if result.Bookmarks == nil {
+		result.Bookmarks = []model.MaterialBookmark{}
+	}
+	return result, nil
+}
+
+func CreateBookmark(materialID uint64, input BookmarkInput, userID uint64) (*model.MaterialBookmark, error) {
+	if err := authorize(userID, PermMaterialView, materialID); err != nil {
+		return nil, err
+	}
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	if _, err := validateAnchor(material, input.Anchor, false); err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimSpace(input.Label)
+	if label == "" {
+		label = material.Title
+		if position := anchorLabel(input.Anchor); position != "" {
+			label += " (" + position + ")"
+		}
+	}
+
+	bookmark := &model.MaterialBookmark{
+		UserID:     userID,
+		MaterialID: materialID,
+		Label:      label,
+		Anchor:     input.Anchor,
+	}
+	if err := repository.CreateBookmark(bookmark); err != nil {
+		return nil, err
+	}
+	return bookmark, nil
+}
+
+func DeleteBookmark(bookmarkID uint64, userID uint64) error {
+	bookmark, err := repository.FindBookmarkByID(bookmarkID)
+	if err != nil || bookmark.UserID != userID {
+		return errors.New("bookmark tidak ditemukan")
+	}
+	return repository.DeleteBookmark(bookmarkID)
+}
+
+// GetMyBookmarks mengambil bookmark user; courseID = 0 berarti dari semua kelas.
+func GetMyBookmarks(courseID uint64, userID uint64) ([]model.MaterialBookmark, error) {
+	if courseID != 0 {
+		if err := authorize(userID, PermCourseView, courseID); err != nil {
+			return nil, err
+		}
+	}
+	bookmarks, err := repository.GetUserBookmarks(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if bookmarks == nil {
+		bookmarks = []model.MaterialBookmark{}
+	}
+	return bookmarks, nil
+}
+
+// anchorLabel menuliskan posisi anotasi dalam bentuk yang mudah dibaca.
+func anchorLabel(anchor model.AnnotationAnchor) string {
+	var parts []string
+	if anchor.Page != nil {
+		parts = append(parts, fmt.Sprintf("halaman %d", *anchor.Page))
+	}
+	if anchor.StartOffset != nil && anchor.EndOffset != nil {
+		parts = append(parts, fmt.Sprintf("karakter %d-%d", *anchor.StartOffset, *anchor.EndOffset))
+	}
+	if anchor.StartSec != nil {
+		position := "menit " + formatTimestamp(*anchor.StartSec)
+		if anchor.EndSec != nil && *anchor.EndSec != *anchor.StartSec {
+			position += "-" + formatTimestamp(*anchor.EndSec)
+		}
+		parts = append(parts, position)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatTimestamp(sec int) string {
+	if sec >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", sec/3600, sec%3600/60, sec%60)
+	}
+	return fmt.Sprintf("%02d:%02d", sec/60, sec%60)
+}
+
+// CourseNotesExport adalah ekspor "catatan saya" untuk satu kelas, dikelompokkan per materi
+// mengikuti urutan modul.
+type CourseNotesExport struct {
+	ExportedAt  time.Time             `json:"exported_at"`
+	CourseID    uint64                `json:"course_id"`
+	CourseTitle string                `json:"course_title"`
+	Materials   []MaterialNotesExport `json:"materials"`
+}
+
+type MaterialNotesExport struct {
+	ModuleTitle string                     `json:"module_title"`
+	MaterialID  uint64                     `json:"material_id"`
+	Title       string                     `json:"title"`
+	Type        model.MaterialType         `json:"type"`
+	Annotations []model.MaterialAnnotation `json:"annotations"`
+	Bookmarks   []model.MaterialBookmark   `json:"bookmarks"`
+}
+
+func ExportCourseNotes(courseID uint64, userID uint64) (*CourseNotesExport, error) {
+	if err := authorize(userID, PermCourseView, courseID); err != nil {
+		return nil, err
+	}
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	annotations, err := repository.GetCourseAnnotations(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks, err := repository.GetUserBookmarks(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byMaterial := map[uint64][]model.MaterialAnnotation{}
+	for _, a := range annotations {
+		byMaterial[a.MaterialID] = append(byMaterial[a.MaterialID], a)
+	}
+	bookmarksByMaterial := map[uint64][]model.MaterialBookmark{}
+	for i := len(bookmarks) - 1; i >= 0; i-- { // bookmark diambil terbaru dulu; ekspor urut waktu
+		b := bookmarks[i]
+		b.Material = nil
+		bookmarksByMaterial[b.MaterialID] = append(bookmarksByMaterial[b.MaterialID], b)
+	}
+
+	export := &CourseNotesExport{
+		ExportedAt:  time.Now(),
+		CourseID:    course.ID,
+		CourseTitle: course.Title,
+		Materials:   []MaterialNotesExport{},
+	}
+	for _, module := range course.Modules {
+		for _, material := range module.Materials {
+			notes, marks := byMaterial[material.ID], bookmarksByMaterial[material.ID]
+			if len(notes) == 0 && len(marks) == 0 {
+				continue
+			}
+			if notes == nil {
+				notes = []model.MaterialAnnotation{}
+			}
+			if marks == nil {
+				marks = []model.MaterialBookmark{}
+			}
+			export.Materials = append(export.Materials, MaterialNotesExport{
+				ModuleTitle: module.Title,
+				MaterialID:  material.ID,
+				Title:       material.Title,
+				Type:        material.Type,
+				Annotations: notes,
+				Bookmarks:   marks,
+			})
+		}
+	}
+	return export, nil
+}
+
+// CourseNotesMarkdown menyusun ekspor catatan sebagai Markdown sederhana: satu judul per modul
+// dan materi, tanpa tabel, agar tetap nyaman dibaca dengan screen reader.
+func CourseNotesMarkdown(export *CourseNotesExport) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Catatan Saya: %s\n\n", export.CourseTitle)
+	fmt.Fprintf(&b, "Diekspor pada %s\n", export.ExportedAt.Format("02-01-2006 15:04"))
+	if len(export.Materials) == 0 {
+		b.WriteString("\nBelum ada catatan, sorotan, atau bookmark di kelas ini.\n")
+	}
+
+	currentModule := ""
+	for _, m := range export.Materials {
+		if m.ModuleTitle != currentModule {
+			currentModule = m.ModuleTitle
+			fmt.Fprintf(&b, "\n## %s\n", currentModule)
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", m.Title)
+
+		for _, a := range m.Annotations {
+			label := "Catatan"
+			if a.Kind == model.AnnotationHighlight {
+				label = "Sorotan"
+			}
+			if position := anchorLabel(a.Anchor); position != "" {
+				label += " (" + position + ")"
+			}
+			line := "- " + label + ":"
+			if a.Quote != "" {
+				line += ` "` + strings.Join(strings.Fields(a.Quote), " ") + `"`
+			}
+			b.WriteString(line + "\n")
+			if a.Note != "" {
+				for _, noteLine := range strings.Split(a.Note, "\n") {
+					b.WriteString("  " + noteLine + "\n")
+				}
+			}
+		}
+		for _, bm := range m.Bookmarks {
+			b.WriteString("- Bookmark: " + bm.Label + "\n")
+		}
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestValidateAnchor(t *testing.T) {
+	pdf := &model.Material{Type: model.TypePDF, Media: model.MediaMetadata{PageCount: 10}}
+	text := &model.Material{Type: model.TypeText, RawContent: "Halo dünia"}
+	audio := &model.Material{Type: model.TypeAudio, DurationMin: 2}
+	image := &model.Material{Type: model.TypeImage}
+
+	tests := []struct {
+		name      string
+		material  *model.Material
+		anchor    model.AnnotationAnchor
+		required  bool
+		wantQuote string
+		wantErr   bool
+	}{
+		{"pdf halaman valid", pdf, model.AnnotationAnchor{Page: intPtr(3)}, true, "", false},
+		{"pdf halaman di luar jangkauan", pdf, model.AnnotationAnchor{Page: intPtr(11)}, true, "", true},
+		{"pdf halaman nol", pdf, model.AnnotationAnchor{Page: intPtr(0)}, true, "", true},
+		{"pdf sorotan tanpa halaman", pdf, model.AnnotationAnchor{}, true, "", true},
+		{"pdf catatan tanpa posisi", pdf, model.AnnotationAnchor{}, false, "", false},
+		{"pdf dengan waktu", pdf, model.AnnotationAnchor{Page: intPtr(1), StartSec: intPtr(5)}, false, "", true},
+		{"teks kutipan per rune", text, model.AnnotationAnchor{StartOffset: intPtr(5), EndOffset: intPtr(10)}, true, "dünia", false},
+		{"teks rentang terbalik", text, model.AnnotationAnchor{StartOffset: intPtr(4), EndOffset: intPtr(2)}, true, "", true},
+		{"teks hanya awal", text, model.AnnotationAnchor{StartOffset: intPtr(1)}, false, "", true},
+		{"teks melewati isi", text, model.AnnotationAnchor{StartOffset: intPtr(0), EndOffset: intPtr(11)}, true, "", true},
+		{"teks sorotan tanpa rentang", text, model.AnnotationAnchor{}, true, "", true},
+		{"teks dengan halaman", text, model.AnnotationAnchor{Page: intPtr(1)}, false, "", true},
+		{"posisi negatif", text, model.AnnotationAnchor{StartOffset: intPtr(-1), EndOffset: intPtr(2)}, true, "", true},
+		{"audio rentang valid", audio, model.AnnotationAnchor{StartSec: intPtr(10), EndSec: intPtr(120)}, true, "", false},
+		{"audio melebihi durasi", audio, model.AnnotationAnchor{StartSec: intPtr(10), EndSec: intPtr(121)}, true, "", true},
+		{"audio akhir sebelum awal", audio, model.AnnotationAnchor{StartSec: intPtr(30), EndSec: intPtr(20)}, true, "", true},
+		{"audio hanya akhir", audio, model.AnnotationAnchor{EndSec: intPtr(20)}, false, "", true},
+		{"gambar catatan tanpa posisi", image, model.AnnotationAnchor{}, false, "", false},
+		{"gambar tidak dapat disorot", image, model.AnnotationAnchor{}, true, "", true},
+		{"gambar dengan halaman", image, model.AnnotationAnchor{Page: intPtr(1)}, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := validateAnchor(tt.material, tt.anchor, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateAnchor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if quote != tt.wantQuote {
+				t.Errorf("validateAnchor() quote = %q, want %q", quote, tt.wantQuote)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"path"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AnnouncementAttachmentInput struct {
+	Name    string `json:"name" binding:"required"`
+	URL     string `json:"url" binding:"required,url"` // URL hasil POST /upload
+	AltText string `json:"alt_text"`                   // Wajib untuk lampiran gambar
+}
+
+type AnnouncementInput struct {
+	Title       string                        `json:"title" binding:"required"`
+	Content     string                        `json:"content" binding:"required"` // Rich text (HTML)
+	Pinned      bool                          `json:"pinned"`
+	PublishAt   *time.Time                    `json:"publish_at"` // Kosong = terbit sekarang
+	Attachments []AnnouncementAttachmentInput `json:"attachments" binding:"dive"`
+}
+
+// buildAnnouncementAttachments memvalidasi lampiran dan membaca metadatanya. Lampiran gambar wajib
+// punya alt text; kegagalan membaca metadata tidak menggagalkan pengumuman.
+func buildAnnouncementAttachments(inputs []AnnouncementAttachmentInput) ([]model.AnnouncementAttachment, error) {
+	attachments := []model.AnnouncementAttachment{}
+	for _, in := range inputs {
+		attachment := model.AnnouncementAttachment{
+			Name:    strings.TrimSpace(in.Name),
+			URL:     in.URL,
+			AltText: strings.TrimSpace(in.AltText),
+		}
+
+		if info, err := utils.ProbeMedia(in.URL); err == nil {
+			attachment.MimeType = info.MimeType
+			attachment.FileSize = info.FileSize
+		}
+		isImage := strings.HasPrefix(attachment.MimeType, "image/")
+		if attachment.MimeType == "" {
+			isImage = ValidateMaterialUpload(model.TypeImage, path.Base(in.URL)) == nil ||
+				ValidateMaterialUpload(model.TypeImage, in.Name) == nil
+		}
+		if isImage && attachment.AltText == "" {
+			return nil, errors.New("input pengumuman tidak valid: alt text wajib diisi untuk lampiran gambar " + attachment.Name)
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func CreateAnnouncement(courseID uint64, input AnnouncementInput, userID uint64) (*model.Announcement, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+
+	content := utils.SanitizeHTML(input.Content)
+	if utils.HTMLToText(content) == "" {
+		return nil, errors.New("input pengumuman tidak valid: isi pengumuman kosong")
+	}
+	attachments, err := buildAnnouncementAttachments(input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	announcement := &model.Announcement{
+		CourseID:    courseID,
+		AuthorID:    userID,
+		Title:       input.Title,
+		Content:     content,
+		Pinned:      input.Pinned,
+		PublishAt:   time.Now(),
+		Attachments: attachments,
+	}
+	if input.PublishAt != nil {
+		announcement.PublishAt = *input.PublishAt
+	}
+	if err := repository.CreateAnnouncement(announcement); err != nil {
+		return nil, err
+	}
+
+	return findAnnouncementForStaff(announcement.ID)
+}
+
+func UpdateAnnouncement(announcementID uint64, input AnnouncementInput, userID uint64) (*model.Announcement, error) {
+	if err := authorize(userID, PermAnnouncementEdit, announcementID); err != nil {
+		return nil, err
+	}
+
+	announcement, err := repository.FindAnnouncementByID(announcementID)
+	if err != nil {
+		return nil, errors.New("pengumuman tidak ditemukan")
+	}
+
+	content := utils.SanitizeHTML(input.Content)
+	if utils.HTMLToText(content) == "" {
+		return nil, errors.New("input pengumuman tidak valid: isi pengumuman kosong")
+	}
+	attachments, err := buildAnnouncementAttachments(input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	announcement.Title = input.Title
+	announcement.Content = content
+	announcement.Pinned = input.Pinned
+	if input.PublishAt != nil {
+		announcement.PublishAt = *input.PublishAt
+	}
+	announcement.Attachments = attachments
+	if err := repository.UpdateAnnouncement(announcement); err != nil {
+		return nil, err
+	}
+
+	return findAnnouncementForStaff(announcementID)
+}
+
+func DeleteAnnouncement(announcementID uint64, userID uint64) error {
+	if err := authorize(userID, PermAnnouncementEdit, announcementID); err != nil {
+		return err
+	}
+	return repository.DeleteAnnouncement(announcementID)
+}
+
+func findAnnouncementForStaff(announcementID uint64) (*model.Announcement, error) {
+	announcement, err := repository.FindAnnouncementByID(announcementID)
+	if err != nil {
+		return nil, errors.New("pengumuman tidak ditemukan")
+	}
+	announcement.IsPublished = !announcement.PublishAt.After(time.Now())
+	return announcement, nil
+}
+
+// GetCourseAnnouncementsForStaff mengembalikan semua pengumuman, termasuk yang masih terjadwal.
+func GetCourseAnnouncementsForStaff(courseID uint64, userID uint64) ([]model.Announcement, error) {
+	if err := authorize(userID, PermCourseTeach, courseID); err != nil {
+		return nil, err
+	}
+
+	announcements, err := repository.GetCourseAnnouncements(courseID, nil)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range announcements {
+		announcements[i].IsPublished = !announcements[i].PublishAt.After(now)
+	}
+	if announcements == nil {
+		announcements = []model.Announcement{}
+	}
+	return announcements, nil
+}
+
+func GetCourseAnnouncementsForStudent(courseID uint64, userID uint64) ([]model.Announcement, error) {
+	if err := authorize(userID, PermCourseView, courseID); err != nil {
+		return nil, err
+	}
+	return deliverCourseAnnouncements(courseID, userID, studentAccessibilityProfile(userID))
+}
+
+// studentAccessibilityProfile mengambil profil aksesibilitas user, atau nil jika belum diisi.
+func studentAccessibilityProfile(userID uint64) *model.AccessibilityProfile {
+	if profile, err := repository.FindAccessibilityProfileByUserID(userID); err == nil {
+		return profile
+	}
+	return nil
+}
+
+// deliverCourseAnnouncements mengambil pengumuman yang sudah terbit beserta status baca dan
+// bentuk penyajiannya sesuai profile (profil pilihan dosen dalam mode pratinjau).
+func deliverCourseAnnouncements(courseID uint64, userID uint64, profile *model.AccessibilityProfile) ([]model.Announcement, error) {
+	now := time.Now()
+	announcements, err := repository.GetCourseAnnouncements(courseID, &now)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(announcements))
+	for _, a := range announcements {
+		ids = append(ids, a.ID)
+	}
+	read, err := repository.GetReadAnnouncementIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range announcements {
+		announcements[i].IsPublished = true
+		announcements[i].IsRead = read[announcements[i].ID]
+		announcements[i].Delivery = announcementDelivery(&announcements[i], profile)
+	}
+	if announcements == nil {
+		announcements = []model.Announcement{}
+	}
+	return announcements, nil
+}
+
+// announcementDelivery menyesuaikan pengumuman dengan profil aksesibilitas penerima:
+//   - tuna netra / pengguna screen reader: teks polos dan kalimat siap TTS
+//   - kesulitan kognitif / mode fokus: teks polos tanpa format
+//   - tuna rungu / notifikasi visual: tanda agar klien memakai notifikasi visual
+func announcementDelivery(announcement *model.Announcement, profile *model.AccessibilityProfile) *model.AnnouncementDelivery {
+	delivery := &model.AnnouncementDelivery{Format: model.AnnouncementFormatRich}
+	if profile == nil {
+		return delivery
+	}
+
+	plain := profile.VisionImpaired || profile.ScreenReaderCompatible || profile.CognitiveImpaired || profile.FocusMode
+	if plain {
+		delivery.Format = model.AnnouncementFormatPlain
+		delivery.PlainText = announcementPlainText(announcement)
+	}
+	if profile.VisionImpaired || profile.ScreenReaderCompatible {
+		delivery.SpeechText = announcementSpeechText(announcement)
+	}
+	delivery.VisualAlert = profile.HearingImpaired || profile.VisualNotifications
+	return delivery
+}
+
+func announcementPlainText(announcement *model.Announcement) string {
+	parts := []string{announcement.Title, utils.HTMLToText(announcement.Content)}
+	if len(announcement.Attachments) > 0 {
+		lines := []string{"Lampiran:"}
+		for _, a := range announcement.Attachments {
+			line := "- " + a.Name
+			if a.AltText != "" {
+				line += " (" + a.AltText + ")"
+			}
+			lines = append(lines, line)
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// announcementSpeechText menyusun teks untuk TTS: baris dan butir daftar diakhiri tanda baca
+// agar ada jeda saat dibacakan, dan lampiran disebutkan jumlah serta deskripsinya.
+func announcementSpeechText(announcement *model.Announcement) string {
+	sentences := []string{"Pengumuman: " + announcement.Title}
+	for _, line := range strings.Split(utils.HTMLToText(announcement.Content), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		if line != "" {
+			sentences = append(sentences, line)
+		}
+	}
+
+	if n := len(announcement.Attachments); n > 0 {
+		sentences = append(sentences, "Terdapat "+pluralAttachment(n))
+		for _, a := range announcement.Attachments {
+			description := a.Name
+			if a.AltText != "" {
+				description += ", " + a.AltText
+			}
+			sentences = append(sentences, "Lampiran "+description)
+		}
+	}
+
+	for i, s := range sentences {
+		if !strings.HasSuffix(s, ".") && !strings.HasSuffix(s, "?") && !strings.HasSuffix(s, "!") && !strings.HasSuffix(s, ":") {
+			sentences[i] = s + "."
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+func pluralAttachment(n int) string {
+	if n == 1 {
+		return "satu lampiran"
+	}
+	return strconv.Itoa(n) + " lampiran"
+}
+
+// MarkAnnouncementRead menandai pengumuman sudah dibaca oleh mahasiswa.
+func MarkAnnouncementRead(announcementID uint64, userID uint64) error {
+	if err := authorize(userID, PermAnnouncementView, announcementID); err != nil {
+		return err
+	}
+
+	announcement, err := repository.FindAnnouncementByID(announcementID)
+	if err != nil || announcement.PublishAt.After(time.Now()) {
+		return errors.New("pengumuman tidak ditemukan")
+	}
+	return repository.MarkAnnouncementRead(announcementID, userID)
+}
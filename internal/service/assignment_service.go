@@ -21,13 +21,11 @@ type AssignmentInput struct {
 
 func CreateAssignment(courseID uint64, input AssignmentInput, teacherID uint64) (*model.Assignment, error) {
 	// Verify Course Ownership
-	course, err := repository.GetCourseByID(courseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
+	if err := authorize(teacherID, PermCourseEdit, courseID); err != nil {
+		return nil, err
 	}
-
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
 	}
 
 	// Verify Module if provided
@@ -63,13 +61,8 @@ func CreateAssignment(courseID uint64, input AssignmentInput, teacherID uint64)
 }
 
 func GetAssignmentsByCourse(courseID uint64, teacherID uint64) ([]model.Assignment, error) {
-	course, err := repository.GetCourseByID(courseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
-	}
-
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := authorize(teacherID, PermCourseTeach, courseID); err != nil {
+		return nil, err
 	}
 
 	return repository.GetAssignmentsByCourseID(courseID)
@@ -93,69 +86,59 @@ func GetStudentAssignmentsByCourse(courseID uint64, studentID uint64) ([]model.A
 }
 
 func GetAssignmentDetail(assignmentID, userID uint64) (*model.Assignment, error) {
-	assignment, err := repository.GetAssignmentByID(assignmentID)
-	if err != nil {
-		return nil, errors.New("tugas tidak ditemukan")
-	}
-
-	course, err := repository.GetCourseByID(assignment.CourseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
-	}
-
 	// Check permissions
-	isTeacher := course.TeacherID == userID
-	isStudent, err := repository.IsStudentInCourse(course.ID, userID)
-	if err != nil {
+	if err := authorize(userID, PermAssignmentView, assignmentID); err != nil {
 		return nil, err
 	}
+	isTeacher := authorize(userID, PermAssignmentGrade, assignmentID) == nil
 
-	if !isTeacher && !isStudent {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke tugas ini")
+	assignment, err := repository.GetAssignmentByID(assignmentID)
+	if err != nil {
+		return nil, errors.New("tugas tidak ditemukan")
 	}
 
 	// If Student, hide other submissions and set MySubmission
 	if !isTeacher {
-		for _, s := range assignment.Submissions {
-			if s.StudentID == userID {
-				mySub := s
-				assignment.MySubmission = &mySub
-				break
-			}
-		}
-		assignment.Submissions = nil // Clear list for student
+		studentAssignmentView(assignment, userID)
 	}
 
 	return assignment, nil
 }
 
+// studentAssignmentView menyembunyikan pengumpulan mahasiswa lain dan mengisi MySubmission.
+func studentAssignmentView(assignment *model.Assignment, studentID uint64) {
+	for _, s := range assignment.Submissions {
+		if s.StudentID == studentID {
+			mySub := s
+			assignment.MySubmission = &mySub
+			break
+		}
+	}
+	assignment.Submissions = nil // Clear list for student
+}
+
 type GradeInput struct {
 	Grade    float64 `json:"grade"` // Remove binding required as 0 is valid. Use explicit validation if needed. But binding:"required" fails on 0 for some validators? No, usually valid. But let's be safe.
 	Feedback string  `json:"feedback"`
 }
 
-func GradeSubmission(submissionID uint64, input GradeInput, teacherID uint64) (*model.Submission, error) {
-	// 1. Get Submission
+func GradeSubmission(submissionID uint64, input GradeInput, teacherID uint64, meta SessionMeta) (*model.Submission, error) {
+	// 1. Verify ownership
+	if err := authorize(teacherID, PermSubmissionGrade, submissionID); err != nil {
+		return nil, err
+	}
+
+	// 2. Get Submission & Assignment
 	submission, err := repository.GetSubmissionByID(submissionID)
 	if err != nil {
 		return nil, errors.New("submission tidak ditemukan")
 	}
 
-	// 2. Get Assignment & Course to verify ownership
 	assignment, err := repository.GetAssignmentByID(submission.AssignmentID)
 	if err != nil {
 		return nil, errors.New("assignment not found")
 	}
 
-	course, err := repository.GetCourseByID(assignment.CourseID)
-	if err != nil {
-		return nil, errors.New("course not found")
-	}
-
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
-	}
-
 	// 3. Update Grade
 	// Validate Grade vs MaxPoints
 	if input.Grade < 0 || input.Grade > float64(assignment.MaxPoints) {
@@ -163,6 +146,8 @@ func GradeSubmission(submissionID uint64, input GradeInput, teacherID uint64) (*
 		return nil, errors.New("nilai tidak valid (melebihi batas maksimal)")
 	}
 
+	before := map[string]interface{}{"grade": submission.Grade, "feedback": submission.Feedback}
+
 	submission.Grade = input.Grade
 	submission.Feedback = input.Feedback
 
@@ -170,27 +155,25 @@ func GradeSubmission(submissionID uint64, input GradeInput, teacherID uint64) (*
 		return nil, err
 	}
 
+	recordAudit(teacherID, meta, auditEntry{
+		Action:     model.AuditSubmissionGrade,
+		TargetType: "submission",
+		TargetID:   submission.ID,
+		CourseID:   &assignment.CourseID,
+		Before:     before,
+		After:      map[string]interface{}{"grade": submission.Grade, "feedback": submission.Feedback},
+	})
+
 	return submission, nil
 }
 
 func GetAssignmentSubmissions(assignmentID uint64, teacherID uint64) ([]model.Submission, error) {
-	// 1. Get Assignment
-	assignment, err := repository.GetAssignmentByID(assignmentID)
-	if err != nil {
-		return nil, errors.New("tugas tidak ditemukan")
-	}
-
-	// 2. Get Course to verify ownership
-	course, err := repository.GetCourseByID(assignment.CourseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
-	}
-
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	// 1. Verify ownership
+	if err := authorize(teacherID, PermAssignmentGrade, assignmentID); err != nil {
+		return nil, err
 	}
 
-	// 3. Get Submissions
+	// 2. Get Submissions
 	return repository.GetSubmissionsByAssignmentID(assignmentID)
 }
 
@@ -208,13 +191,9 @@ func SubmitAssignment(assignmentID uint64, input SubmissionInput, studentID uint
 	}
 
 	// 2. Verify Student Enrollment
-	inCourse, err := repository.IsStudentInCourse(assignment.CourseID, studentID)
-	if err != nil {
+	if err := authorize(studentID, PermAssignmentSubmit, assignmentID); err != nil {
 		return nil, err
 	}
-	if !inCourse {
-		return nil, errors.New("unauthorized: anda belum bergabung di kelas ini")
-	}
 
 	// 3. Check Deadline
 	if !assignment.AllowLate && time.Now().After(assignment.Deadline) {
@@ -270,18 +249,13 @@ func SubmitAssignment(assignmentID uint64, input SubmissionInput, studentID uint
 }
 
 func UpdateAssignment(assignmentID uint64, input AssignmentInput, teacherID uint64) (*model.Assignment, error) {
-	assignment, err := repository.GetAssignmentByID(assignmentID)
-	if err != nil {
-		return nil, errors.New("tugas tidak ditemukan")
+	if err := authorize(teacherID, PermAssignmentEdit, assignmentID); err != nil {
+		return nil, err
 	}
 
-	course, err := repository.GetCourseByID(assignment.CourseID)
+	assignment, err := repository.GetAssignmentByID(assignmentID)
 	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
-	}
-
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+		return nil, errors.New("tugas tidak ditemukan")
 	}
 
 	if input.ModuleID != nil {
@@ -289,7 +263,7 @@ func UpdateAssignment(assignmentID uint64, input AssignmentInput, teacherID uint
 		if err != nil {
 			return nil, errors.New("modul tidak ditemukan")
 		}
-		if module.CourseID != course.ID {
+		if module.CourseID != assignment.CourseID {
 			return nil, errors.New("modul tidak valid untuk kelas ini")
 		}
 		assignment.ModuleID = input.ModuleID
@@ -311,18 +285,8 @@ func UpdateAssignment(assignmentID uint64, input AssignmentInput, teacherID uint
 }
 
 func DeleteAssignment(assignmentID uint64, teacherID uint64) error {
-	assignment, err := repository.GetAssignmentByID(assignmentID)
-	if err != nil {
-		return errors.New("tugas tidak ditemukan")
-	}
-
-	course, err := repository.GetCourseByID(assignment.CourseID)
-	if err != nil {
-		return errors.New("kelas tidak ditemukan")
-	}
-
-	if course.TeacherID != teacherID {
-		return errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := authorize(teacherID, PermAssignmentEdit, assignmentID); err != nil {
+		return err
 	}
 
 	return repository.DeleteAssignment(assignmentID)
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"reflect"
+)
+
+type AuditLogListResponse struct {
+	Logs       []model.AuditLog `json:"logs"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
+
+// auditEntry menjelaskan satu aksi yang dicatat. Before/After cukup berisi kolom yang
+// relevan; recordAudit hanya menyimpan kolom yang nilainya berubah.
+type auditEntry struct {
+	Action     model.AuditAction
+	TargetType string
+	TargetID   uint64
+	CourseID   *uint64
+	Before     map[string]interface{}
+	After      map[string]interface{}
+}
+
+// recordAudit menulis audit log. Kegagalan menulis hanya dicatat di log server
+// agar aksi utama yang sudah berhasil tidak ikut dibatalkan.
+func recordAudit(actorID uint64, meta SessionMeta, entry auditEntry) {
+	before, after := diffFields(entry.Before, entry.After)
+
+	userAgent := meta.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	auditLog := &model.AuditLog{
+		ActorID:    &actorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		CourseID:   entry.CourseID,
+		Before:     marshalAuditData(before),
+		After:      marshalAuditData(after),
+		IPAddress:  meta.IPAddress,
+		UserAgent:  userAgent,
+	}
+	if actor, err := repository.FindUserByID(actorID); err == nil {
+		auditLog.ActorRole = string(actor.Role)
+	}
+
+	if err := repository.CreateAuditLog(auditLog); err != nil {
+		log.Printf("Failed to write audit log %s for %s #%d: %v\n", entry.Action, entry.TargetType, entry.TargetID, err)
+	}
+}
+
+// diffFields membuang kolom yang sama di before dan after. Jika salah satu sisi kosong
+// (mis. aksi hapus), sisi lainnya disimpan utuh.
+func diffFields(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, newValue := range after {
+		oldValue, ok := before[key]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changedBefore[key] = oldValue
+			changedAfter[key] = newValue
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+func marshalAuditData(data map[string]interface{}) []byte {
+	if data == nil {
+		return nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func ListAuditLogs(filter repository.AuditLogFilter, page, limit int) (*AuditLogListResponse, error) {
+	offset := (page - 1) * limit
+	logs, total, err := repository.FindAuditLogs(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if logs == nil {
+		logs = []model.AuditLog{}
+	}
+
+	totalPage := int((total + int64(limit) - 1) / int64(limit))
+
+	return &AuditLogListResponse{
+		Logs: logs,
+		Pagination: PaginationMeta{
+			CurrentPage: page,
+			TotalPage:   totalPage,
+			TotalItems:  total,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// ListCourseAuditLogs menampilkan audit log satu kelas untuk pengelola kelas (pemilik/co-teacher).
+func ListCourseAuditLogs(courseID uint64, userID uint64, filter repository.AuditLogFilter, page, limit int) (*AuditLogListResponse, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+
+	filter.CourseID = &courseID
+	return ListAuditLogs(filter, page, limit)
+}
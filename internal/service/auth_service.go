@@ -1,12 +1,12 @@
 package service
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
+	"fmt"
 	"ramah-disabilitas-be/internal/model"
 	"ramah-disabilitas-be/internal/repository"
 	"ramah-disabilitas-be/pkg/utils"
+	"time"
 )
 
 type RegisterInput struct {
@@ -22,6 +22,36 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type ResendVerificationInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required,min=6"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// SessionMeta berisi informasi request yang disimpan bersama sesi login.
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+type AuthTokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // detik
+}
+
 func Register(input RegisterInput) (*model.User, error) {
 	// Check if email already exists
 	_, err := repository.FindUserByEmail(input.Email)
@@ -44,19 +74,14 @@ func Register(input RegisterInput) (*model.User, error) {
 		return nil, errors.New("role tidak valid (pilih 'dosen' atau 'mahasiswa')")
 	}
 
-	// Generate verification token
-	tokenBytes := make([]byte, 32)
-	rand.Read(tokenBytes)
-	token := hex.EncodeToString(tokenBytes)
-
 	user := &model.User{
-		Name:              input.Name,
-		Email:             input.Email,
-		Password:          hashedPassword,
-		Role:              userRole,
-		IsVerified:        false,
-		VerificationToken: token,
+		Name:       input.Name,
+		Email:      input.Email,
+		Password:   hashedPassword,
+		Role:       userRole,
+		IsVerified: false,
 	}
+	token := setVerificationToken(user)
 
 	if err := repository.CreateUser(user); err != nil {
 		return nil, err
@@ -75,26 +100,302 @@ func Register(input RegisterInput) (*model.User, error) {
 	return user, nil
 }
 
-func Login(input LoginInput) (*model.User, string, error) {
+const (
+	maxFailedLoginAttempts = 5
+	maxLockoutDuration     = time.Hour
+	ipAttemptWindow        = 15 * time.Minute
+	maxFailedAttemptsPerIP = 20
+)
+
+func Login(input LoginInput, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	// 1. Throttle per IP (menahan tebakan massal ke banyak akun dari satu sumber)
+	if meta.IPAddress != "" {
+		failedFromIP, err := repository.CountFailedLoginAttemptsByIP(meta.IPAddress, time.Now().Add(-ipAttemptWindow))
+		if err != nil {
+			return nil, nil, err
+		}
+		if failedFromIP >= maxFailedAttemptsPerIP {
+			return nil, nil, errors.New("terlalu banyak percobaan login gagal dari alamat ini. coba lagi nanti")
+		}
+	}
+
 	user, err := repository.FindUserByEmail(input.Email)
 	if err != nil {
-		return nil, "", errors.New("email atau password salah")
+		recordLoginAttempt(input.Email, nil, meta, false)
+		return nil, nil, errors.New("email atau password salah")
+	}
+
+	// 2. Lockout per akun
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		recordLoginAttempt(input.Email, &user.ID, meta, false)
+		return nil, nil, lockedError(*user.LockedUntil)
 	}
 
 	if !utils.CheckPasswordHash(input.Password, user.Password) {
-		return nil, "", errors.New("email atau password salah")
+		recordLoginAttempt(input.Email, &user.ID, meta, false)
+		if lockedUntil := registerFailedLogin(user); lockedUntil != nil {
+			return nil, nil, lockedError(*lockedUntil)
+		}
+		return nil, nil, errors.New("email atau password salah")
 	}
 
 	if !user.IsVerified {
-		return nil, "", errors.New("email belum diverifikasi. silahkan cek inbox anda")
+		return nil, nil, errors.New("email belum diverifikasi. silahkan cek inbox anda")
+	}
+
+	return continueLogin(user, meta)
+}
+
+// continueLogin dijalankan setelah faktor pertama (password atau SSO) berhasil.
+func continueLogin(user *model.User, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	if user.DeactivatedAt != nil {
+		return nil, nil, errors.New("akun anda telah dinonaktifkan. silahkan hubungi admin")
+	}
+
+	// 3. Langkah kedua (TOTP). Hitungan gagal baru di-reset setelah kode 2FA benar.
+	if user.TwoFactorEnabled {
+		challenge, err := utils.GenerateTwoFactorChallenge(user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return user, nil, &TwoFactorRequiredError{
+			ChallengeToken: challenge,
+			ExpiresIn:      int64(utils.TwoFactorChallengeTTL.Seconds()),
+		}
+	}
+
+	return completeLogin(user, meta)
+}
+
+// completeLogin mencatat login berhasil, me-reset hitungan gagal, lalu membuka sesi.
+func completeLogin(user *model.User, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	recordLoginAttempt(user.Email, &user.ID, meta, true)
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		repository.UpdateUserFields(user.ID, map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		})
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+	}
+
+	tokens, err := startSession(user, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+func recordLoginAttempt(email string, userID *uint64, meta SessionMeta, success bool) {
+	repository.CreateLoginAttempt(&model.LoginAttempt{
+		Email:     email,
+		UserID:    userID,
+		IPAddress: meta.IPAddress,
+		Success:   success,
+	})
+}
+
+// registerFailedLogin menambah hitungan gagal dan mengunci akun secara progresif:
+// 1 menit setelah 5 kali gagal, lalu berlipat dua setiap kegagalan berikutnya (maks. 1 jam).
+// Mengembalikan waktu akhir penguncian bila akun baru saja dikunci.
+func registerFailedLogin(user *model.User) *time.Time {
+	user.FailedLoginAttempts++
+	fields := map[string]interface{}{"failed_login_attempts": user.FailedLoginAttempts}
+
+	var lockedUntil *time.Time
+	if user.FailedLoginAttempts >= maxFailedLoginAttempts {
+		duration := maxLockoutDuration
+		if shift := user.FailedLoginAttempts - maxFailedLoginAttempts; shift < 6 {
+			duration = time.Minute << shift
+		}
+		until := time.Now().Add(duration)
+		lockedUntil = &until
+		fields["locked_until"] = until
+	}
+
+	repository.UpdateUserFields(user.ID, fields)
+	return lockedUntil
+}
+
+func lockedError(until time.Time) error {
+	minutes := int(time.Until(until).Minutes()) + 1
+	return fmt.Errorf("akun terkunci sementara karena terlalu banyak percobaan login gagal. coba lagi dalam %d menit", minutes)
+}
+
+// UnlockUser membuka kunci akun yang terkunci karena percobaan login gagal (aksi admin).
+func UnlockUser(userID uint64) error {
+	if _, err := repository.FindUserByID(userID); err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	return repository.UpdateUserFields(userID, map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	})
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required,min=6"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
+}
+
+// ChangePassword mengganti password user yang sedang login, mencabut semua sesi lama,
+// lalu membuka sesi baru untuk perangkat saat ini.
+func ChangePassword(userID uint64, input ChangePasswordInput, meta SessionMeta) (*AuthTokens, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if !utils.CheckPasswordHash(input.CurrentPassword, user.Password) {
+		return nil, errors.New("password lama tidak sesuai")
+	}
+
+	if input.CurrentPassword == input.Password {
+		return nil, errors.New("password baru harus berbeda dari password lama")
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.UpdateUserFields(user.ID, map[string]interface{}{
+		"password":             hashedPassword,
+		"must_change_password": false,
+	}); err != nil {
+		return nil, err
+	}
+	user.MustChangePassword = false
+
+	if err := RevokeUserSessions(user.ID); err != nil {
+		return nil, err
+	}
+
+	return startSession(user, meta)
+}
+
+// startSession membuat sesi baru beserta pasangan access/refresh token pertamanya.
+func startSession(user *model.User, meta SessionMeta) (*AuthTokens, error) {
+	userAgent := meta.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	session := &model.UserSession{
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		IPAddress: meta.IPAddress,
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := repository.CreateSession(session); err != nil {
+		return nil, err
 	}
 
-	token, err := utils.GenerateToken(user.ID, string(user.Role))
+	return issueTokens(user, session.ID)
+}
+
+func issueTokens(user *model.User, sessionID uint64) (*AuthTokens, error) {
+	accessToken, err := utils.GenerateToken(user.ID, string(user.Role), sessionID, utils.TokenRestrictions{
+		MustChangePassword: user.MustChangePassword,
+		TwoFactorSetup:     !user.TwoFactorEnabled && twoFactorRequiredForRole(user.Role),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := utils.GenerateRandomToken(32)
+	record := &model.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := repository.CreateRefreshToken(record); err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RefreshSession menukar refresh token dengan pasangan token baru (rotasi).
+// Jika refresh token yang sudah pernah dipakai dikirim ulang, seluruh sesi dicabut
+// karena kemungkinan besar token tersebut telah bocor.
+func RefreshSession(refreshToken string) (*AuthTokens, error) {
+	record, err := repository.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	if record.UsedAt != nil {
+		repository.RevokeSession(record.SessionID)
+		return nil, errors.New("refresh token sudah digunakan, sesi dicabut. silahkan login ulang")
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return nil, errors.New("refresh token kadaluarsa. silahkan login ulang")
+	}
+
+	if !repository.IsSessionActive(record.SessionID) {
+		return nil, errors.New("sesi sudah berakhir. silahkan login ulang")
+	}
+
+	marked, err := repository.MarkRefreshTokenUsed(record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		repository.RevokeSession(record.SessionID)
+		return nil, errors.New("refresh token sudah digunakan, sesi dicabut. silahkan login ulang")
+	}
+
+	user, err := repository.FindUserByID(record.Session.UserID)
 	if err != nil {
-		return nil, "", err
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if user.DeactivatedAt != nil {
+		repository.RevokeSession(record.SessionID)
+		return nil, errors.New("akun anda telah dinonaktifkan. silahkan hubungi admin")
 	}
 
-	return user, token, nil
+	return issueTokens(user, record.SessionID)
+}
+
+func Logout(sessionID uint64) error {
+	return repository.RevokeSession(sessionID)
+}
+
+// RevokeUserSessions mencabut semua sesi aktif milik user (mis. setelah ganti password
+// atau atas permintaan admin).
+func RevokeUserSessions(userID uint64) error {
+	return repository.RevokeSessionsByUserID(userID)
+}
+
+func IsSessionActive(sessionID uint64) bool {
+	return repository.IsSessionActive(sessionID)
+}
+
+const (
+	verificationTokenTTL       = 24 * time.Hour
+	verificationResendCooldown = 2 * time.Minute
+)
+
+// setVerificationToken membuat token verifikasi baru beserta masa berlakunya pada user.
+func setVerificationToken(user *model.User) string {
+	token := utils.GenerateRandomToken(32)
+	now := time.Now()
+	expiresAt := now.Add(verificationTokenTTL)
+
+	user.VerificationToken = token
+	user.VerificationExpiresAt = &expiresAt
+	user.VerificationSentAt = &now
+	return token
 }
 
 func VerifyEmail(token string) error {
@@ -107,12 +408,106 @@ func VerifyEmail(token string) error {
 		return errors.New("email sudah terverifikasi")
 	}
 
+	if user.VerificationExpiresAt != nil && time.Now().After(*user.VerificationExpiresAt) {
+		return errors.New("token verifikasi kadaluarsa. silahkan minta tautan verifikasi baru")
+	}
+
 	user.IsVerified = true
 	user.VerificationToken = ""
+	user.VerificationExpiresAt = nil
 
 	return repository.UpdateUser(user)
 }
 
+// ResendVerification mengirim ulang tautan verifikasi dengan token baru.
+// Email yang tidak terdaftar, sudah terverifikasi, atau masih dalam jeda kirim ulang tidak
+// menghasilkan error (dan tidak mengirim email) agar akun tidak bisa ditebak.
+func ResendVerification(email string) error {
+	user, err := repository.FindUserByEmail(email)
+	if err != nil || user.IsVerified {
+		return nil
+	}
+
+	if user.VerificationSentAt != nil && time.Now().Before(user.VerificationSentAt.Add(verificationResendCooldown)) {
+		return nil
+	}
+
+	token := setVerificationToken(user)
+	if err := repository.UpdateUser(user); err != nil {
+		return err
+	}
+
+	return utils.SendVerificationEmail(user.Email, token)
+}
+
+const passwordResetTTL = time.Hour
+
+// ForgotPassword mengirim tautan reset password. Email yang tidak terdaftar tidak
+// menghasilkan error agar endpoint tidak bisa dipakai untuk menebak akun.
+func ForgotPassword(email string) error {
+	user, err := repository.FindUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	if err := repository.DeleteUnusedPasswordResetTokens(user.ID); err != nil {
+		return err
+	}
+
+	token := utils.GenerateRandomToken(32)
+	record := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := repository.CreatePasswordResetToken(record); err != nil {
+		return err
+	}
+
+	return utils.SendPasswordResetEmail(user.Email, token)
+}
+
+func ResetPassword(input ResetPasswordInput) error {
+	record, err := repository.FindPasswordResetTokenByHash(utils.HashToken(input.Token))
+	if err != nil || record.UsedAt != nil {
+		return errors.New("token reset password tidak valid")
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return errors.New("token reset password kadaluarsa")
+	}
+
+	user, err := repository.FindUserByID(record.UserID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	marked, err := repository.MarkPasswordResetTokenUsed(record.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return errors.New("token reset password tidak valid")
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return err
+	}
+
+	// Password dipilih sendiri oleh user, jadi kewajiban ganti password & kunci akun dilepas
+	if err := repository.UpdateUserFields(user.ID, map[string]interface{}{
+		"password":              hashedPassword,
+		"must_change_password":  false,
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}); err != nil {
+		return err
+	}
+
+	return RevokeUserSessions(user.ID)
+}
+
 func GetMe(userID uint64) (*model.User, error) {
 	return repository.FindUserByID(userID)
 }
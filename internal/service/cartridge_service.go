@@ -0,0 +1,463 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/cartridge"
+	"ramah-disabilitas-be/pkg/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tugas hasil impor belum punya deadline (Common Cartridge tidak menyimpannya).
+const importedAssignmentDeadlineDays = 7
+
+type CartridgeImportResult struct {
+	Course      *model.Course    `json:"course"`
+	Format      cartridge.Format `json:"format"`
+	Version     string           `json:"version"`
+	Modules     int              `json:"modules"`
+	Materials   int              `json:"materials"`
+	Assignments int              `json:"assignments"`
+	Warnings    []string         `json:"warnings"`
+}
+
+var cartridgeFilenamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ExportCourseCartridge mengemas modul, materi dan tugas kelas menjadi IMS Common Cartridge 1.3.
+// File materi yang tersimpan di storage lokal ikut dikemas; file di luar server tetap berupa tautan.
+// Deadline, jadwal rilis dan ringkasan AI tidak punya padanan di Common Cartridge sehingga tidak ikut.
+func ExportCourseCartridge(courseID uint64, userID uint64) ([]byte, string, error) {
+	if err := authorize(userID, PermCourseTeach, courseID); err != nil {
+		return nil, "", err
+	}
+
+	course, err := repository.GetCourseForCopy(courseID)
+	if err != nil {
+		return nil, "", errors.New("kelas tidak ditemukan")
+	}
+
+	pkg := &cartridge.Package{Title: course.Title, Description: course.Description}
+	moduleIndex := make(map[uint64]int)
+	for i, module := range course.Modules {
+		moduleIndex[module.ID] = i
+		m := cartridge.Module{Title: module.Title}
+		for _, material := range module.Materials {
+			m.Items = append(m.Items, materialCartridgeItem(material))
+		}
+		pkg.Modules = append(pkg.Modules, m)
+	}
+	for _, assignment := range course.Assignments {
+		item := assignmentCartridgeItem(assignment)
+		if assignment.ModuleID != nil {
+			if idx, ok := moduleIndex[*assignment.ModuleID]; ok {
+				pkg.Modules[idx].Items = append(pkg.Modules[idx].Items, item)
+				continue
+			}
+		}
+		pkg.Items = append(pkg.Items, item)
+	}
+
+	var buf bytes.Buffer
+	if err := cartridge.Write(&buf, pkg); err != nil {
+		return nil, "", err
+	}
+
+	name := strings.Trim(cartridgeFilenamePattern.ReplaceAllString(strings.ToLower(course.Title), "-"), "-")
+	if name == "" {
+		name = "kelas-" + strconv.FormatUint(course.ID, 10)
+	}
+	return buf.Bytes(), name + ".imscc", nil
+}
+
+func materialCartridgeItem(material model.Material) cartridge.Item {
+	if material.Type == model.TypeYoutube {
+		return cartridge.Item{Title: material.Title, Kind: cartridge.ItemLink, URL: material.SourceURL}
+	}
+
+	dir := fmt.Sprintf("materi_%d", material.ID)
+	item := cartridge.Item{Title: material.Title, Kind: cartridge.ItemPage, Href: dir + "/index.html"}
+	page := cartridge.NewPage(material.Title).Meta("material-type", string(material.Type))
+	if material.DurationMin > 0 {
+		page.Meta("duration-min", strconv.Itoa(material.DurationMin))
+	}
+
+	if material.Type != model.TypeText && material.SourceURL != "" {
+		link := material.SourceURL
+		if localPath := localStoragePath(material.SourceURL); localPath != "" {
+			name := filepath.Base(localPath)
+			item.Files = append(item.Files, cartridge.File{Href: dir + "/" + name, SourcePath: localPath})
+			link = (&url.URL{Path: name}).EscapedPath()
+		}
+
+		link = html.EscapeString(link)
+		switch material.Type {
+		case model.TypeImage:
+			page.Raw(`<p><img id="source" src="` + link + `" alt="` + html.EscapeString(material.AltText) + `"/></p>`)
+		case model.TypeAudio:
+			page.Raw(`<p><audio id="source" controls="controls" src="` + link + `"></audio></p>`)
+		default:
+			page.Raw(`<p><a id="source" href="` + link + `">Buka file materi</a></p>`)
+		}
+	}
+
+	if material.AltText != "" {
+		page.Section("alt-text", "Deskripsi", material.AltText)
+	}
+	if material.Transcript != "" {
+		heading := "Transkrip"
+		if material.Type == model.TypeSlides {
+			heading = "Catatan slide"
+		}
+		page.Section("transcript", heading, material.Transcript)
+	}
+	if material.RawContent != "" || material.Type == model.TypeText {
+		page.Section("content", "", material.RawContent)
+	}
+
+	item.Files = append([]cartridge.File{{Href: item.Href, Data: page.Bytes()}}, item.Files...)
+	return item
+}
+
+func assignmentCartridgeItem(assignment model.Assignment) cartridge.Item {
+	instruction := strings.ReplaceAll(html.EscapeString(assignment.Instruction), "\n", "<br/>\n")
+
+	var formats []string
+	if assignment.AllowText {
+		formats = append(formats, "text")
+	}
+	if assignment.AllowFile || assignment.AllowVoice {
+		formats = append(formats, "file")
+	}
+
+	return cartridge.Item{
+		Title: assignment.Title,
+		Kind:  cartridge.ItemAssignment,
+		Assignment: &cartridge.Assignment{
+			Title:       assignment.Title,
+			Instruction: instruction,
+			MaxPoints:   assignment.MaxPoints,
+			Formats:     formats,
+		},
+	}
+}
+
+// localStoragePath mengembalikan path file di disk untuk URL ".../storage/public/...", atau ""
+// jika file tidak disimpan di server ini.
+func localStoragePath(sourceURL string) string {
+	rel, ok := utils.LocalStoragePath(sourceURL)
+	if !ok {
+		return ""
+	}
+	if info, err := os.Stat(rel); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	return rel
+}
+
+// ImportCourseCartridge membuat kelas draft baru dari paket Common Cartridge atau SCORM 1.2.
+// Item level atas menjadi modul; halaman HTML menjadi materi teks, tautan Youtube menjadi materi
+// youtube dan file PDF/audio/gambar dipetakan sesuai ekstensinya. File paket disalin ke
+// storage/public/imports. Item yang tidak bisa dipetakan dilaporkan di Warnings.
+func ImportCourseCartridge(zipPath string, baseURL string, teacherID uint64) (*CartridgeImportResult, error) {
+	archive, err := cartridge.Open(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	importID := strconv.FormatInt(time.Now().UnixNano(), 10)
+	importer := &cartridgeImporter{
+		archive: archive,
+		dir:     filepath.Join("storage", "public", "imports", importID),
+		webDir:  "/storage/public/imports/" + importID + "/",
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		result: &CartridgeImportResult{
+			Format:   archive.Format,
+			Version:  archive.Version,
+			Warnings: []string{},
+		},
+	}
+
+	pkg := archive.Package
+	code, err := generateUniqueClassCode()
+	if err != nil {
+		return nil, err
+	}
+	course := &model.Course{
+		TeacherID:        teacherID,
+		Title:            pkg.Title,
+		Description:      pkg.Description,
+		ClassCode:        code,
+		Status:           model.CourseStatusDraft,
+		EnrollmentPolicy: model.EnrollmentOpen,
+	}
+	if course.Title == "" {
+		course.Title = "Kelas Impor"
+	}
+
+	var assignments []model.Assignment
+	var assignmentModules []int
+	deadline := time.Now().AddDate(0, 0, importedAssignmentDeadlineDays)
+
+	for i, m := range pkg.Modules {
+		module := model.Module{Title: m.Title, Order: i + 1}
+		if module.Title == "" {
+			module.Title = fmt.Sprintf("Modul %d", i+1)
+		}
+		for _, item := range m.Items {
+			if item.Kind == cartridge.ItemAssignment {
+				assignments = append(assignments, importedAssignment(item, deadline))
+				assignmentModules = append(assignmentModules, i)
+				continue
+			}
+			material, ok := importer.material(item)
+			if !ok {
+				continue
+			}
+			material.Order = len(module.Materials) + 1
+			module.Materials = append(module.Materials, material)
+			importer.result.Materials++
+		}
+		course.Modules = append(course.Modules, module)
+	}
+	for _, item := range pkg.Items {
+		if item.Kind == cartridge.ItemAssignment {
+			assignments = append(assignments, importedAssignment(item, deadline))
+			assignmentModules = append(assignmentModules, -1)
+		}
+	}
+
+	if len(course.Modules) == 0 && len(assignments) == 0 {
+		os.RemoveAll(importer.dir)
+		return nil, errors.New("paket tidak valid: tidak ada modul, materi atau tugas yang bisa diimpor")
+	}
+	if len(assignments) > 0 {
+		importer.warn(fmt.Sprintf("deadline %d tugas diatur ke %d hari dari sekarang karena paket tidak menyimpan deadline; sesuaikan sebelum kelas dipublikasikan",
+			len(assignments), importedAssignmentDeadlineDays))
+	}
+
+	if err := repository.CreateCourseCopy(course, assignments, assignmentModules); err != nil {
+		os.RemoveAll(importer.dir)
+		return nil, err
+	}
+
+	importer.result.Course = course
+	importer.result.Modules = len(course.Modules)
+	importer.result.Assignments = len(assignments)
+	return importer.result, nil
+}
+
+type cartridgeImporter struct {
+	archive *cartridge.Archive
+	dir     string // Direktori ekstraksi di disk
+	webDir  string // Path web untuk dir
+	baseURL string
+	result  *CartridgeImportResult
+}
+
+func (im *cartridgeImporter) warn(msg string) {
+	im.result.Warnings = append(im.result.Warnings, msg)
+}
+
+// extract menyalin file paket ke storage dan mengembalikan path web-nya (/storage/public/...)
+// yang belum di-escape, sehingga masih bisa dibaca langsung dari disk (ProbeMedia).
+func (im *cartridgeImporter) extract(href string) (string, error) {
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	if _, err := im.archive.Extract(href, im.dir); err != nil {
+		return "", err
+	}
+	return im.webDir + path.Clean(href), nil
+}
+
+// publicURL mengubah path web hasil extract menjadi URL lengkap seperti unggahan biasa.
+func (im *cartridgeImporter) publicURL(webPath string) string {
+	if strings.HasPrefix(webPath, im.webDir) {
+		return im.baseURL + (&url.URL{Path: webPath}).EscapedPath()
+	}
+	return webPath
+}
+
+func (im *cartridgeImporter) material(item cartridge.Item) (model.Material, bool) {
+	title := item.Title
+	if title == "" {
+		title = "Materi tanpa judul"
+	}
+	material := model.Material{Title: title, Type: model.TypeText}
+
+	switch item.Kind {
+	case cartridge.ItemLink:
+		material.SourceURL = item.URL
+		if utils.ExtractVideoID(item.URL) != "" {
+			material.Type = model.TypeYoutube
+		} else {
+			material.RawContent = item.URL
+		}
+		return material, true
+
+	case cartridge.ItemPage:
+		data, err := im.archive.ReadFile(item.Href)
+		if err != nil {
+			im.warn(fmt.Sprintf("materi %q dilewati: %v", title, err))
+			return material, false
+		}
+		page := cartridge.ParsePage(data)
+		if materialType := model.MaterialType(page.Meta["material-type"]); materialType != "" {
+			return im.exportedPage(material, materialType, item, page)
+		}
+
+		material.RawContent = page.Text
+		if item.SCO || len(item.Files) > 1 {
+			// Halaman dengan aset (gambar, script, SCO) disalin utuh agar bisa dibuka apa adanya.
+			// /storage disajikan dengan CSP sandbox, jadi script paket tidak berjalan di origin API.
+			for _, file := range item.Files {
+				if _, err := im.extract(file.Href); err != nil {
+					im.warn(fmt.Sprintf("file %s pada materi %q gagal disalin: %v", file.Href, title, err))
+				}
+			}
+			material.SourceURL = im.publicURL(im.webDir + item.Href)
+		}
+		if item.SCO {
+			im.warn(fmt.Sprintf("materi %q adalah SCO SCORM; diimpor sebagai teks dengan tautan ke halaman aslinya, halaman dibuka dalam sandbox tanpa script dan pelacakan nilai SCORM tidak dijalankan", title))
+		}
+		return material, true
+
+	case cartridge.ItemFile:
+		webPath, err := im.extract(item.Href)
+		if err != nil {
+			im.warn(fmt.Sprintf("materi %q dilewati: %v", title, err))
+			return material, false
+		}
+		material.SourceURL = webPath
+		switch ext := strings.ToLower(path.Ext(item.Href)); {
+		case ext == ".pdf":
+			material.Type = model.TypePDF
+		default:
+			for _, t := range []model.MaterialType{model.TypeAudio, model.TypeImage} {
+				if ValidateMaterialUpload(t, item.Href) == nil {
+					material.Type = t
+				}
+			}
+		}
+		return im.finishMaterial(material), true
+
+	case cartridge.ItemUnsupported:
+		resourceType := item.ResourceType
+		if resourceType == "" {
+			resourceType = "tanpa resource"
+		}
+		im.warn(fmt.Sprintf("item %q (%s) dilewati: tipe ini belum didukung", title, resourceType))
+	}
+	return material, false
+}
+
+// exportedPage membaca halaman hasil ExportCourseCartridge sehingga tipe, file, alt text dan
+// transkrip materi kembali utuh.
+func (im *cartridgeImporter) exportedPage(material model.Material, materialType model.MaterialType, item cartridge.Item, page cartridge.Page) (model.Material, bool) {
+	material.Type = materialType
+	material.AltText = page.Sections["alt-text"]
+	material.Transcript = page.Sections["transcript"]
+	material.RawContent = page.Sections["content"]
+	material.DurationMin, _ = strconv.Atoi(page.Meta["duration-min"])
+
+	if link := page.Links["source"]; link != "" {
+		href := path.Join(path.Dir(item.Href), link)
+		if !strings.Contains(link, "://") && im.archive.Has(href) {
+			webPath, err := im.extract(href)
+			if err != nil {
+				im.warn(fmt.Sprintf("materi %q dilewati: %v", material.Title, err))
+				return material, false
+			}
+			material.SourceURL = webPath
+		} else {
+			material.SourceURL = link
+		}
+	}
+
+	if materialType == model.TypeText {
+		return material, true
+	}
+	return im.finishMaterial(material), true
+}
+
+// finishMaterial memvalidasi materi media seperti unggahan biasa. Materi yang belum memenuhi
+// syarat aksesibilitas (mis. audio tanpa transkrip) atau filenya tidak terbaca diturunkan menjadi
+// materi teks berisi tautan file, supaya tidak ada materi yang tampil tanpa alternatif teks.
+func (im *cartridgeImporter) finishMaterial(material model.Material) model.Material {
+	if material.Type == model.TypeText {
+		im.warn(fmt.Sprintf("materi %q diimpor sebagai teks berisi tautan file karena formatnya tidak dikenali", material.Title))
+		return im.linkMaterial(material)
+	}
+
+	err := validateMaterialInput(MaterialInput{
+		Type:       material.Type,
+		AltText:    material.AltText,
+		Transcript: material.Transcript,
+	})
+	if err == nil {
+		err = applyMaterialMedia(&material)
+	}
+	if err != nil {
+		im.warn(fmt.Sprintf("materi %q (%s) diimpor sebagai teks berisi tautan file: %v. Ubah tipenya setelah dilengkapi",
+			material.Title, material.Type, err))
+		return im.linkMaterial(material)
+	}
+
+	material.SourceURL = im.publicURL(material.SourceURL)
+	return material
+}
+
+func (im *cartridgeImporter) linkMaterial(material model.Material) model.Material {
+	material.SourceURL = im.publicURL(material.SourceURL)
+	parts := []string{"File: " + material.SourceURL}
+	for _, text := range []string{material.AltText, material.Transcript, material.RawContent} {
+		if strings.TrimSpace(text) != "" {
+			parts = append(parts, text)
+		}
+	}
+	material.Type = model.TypeText
+	material.RawContent = strings.Join(parts, "\n\n")
+	material.Media = model.MediaMetadata{}
+	return material
+}
+
+func importedAssignment(item cartridge.Item, deadline time.Time) model.Assignment {
+	a := item.Assignment
+	assignment := model.Assignment{
+		Title:       a.Title,
+		Instruction: cartridge.ParsePage([]byte(a.Instruction)).Text,
+		Deadline:    deadline,
+		MaxPoints:   a.MaxPoints,
+	}
+	if assignment.Title == "" {
+		assignment.Title = item.Title
+	}
+	if assignment.MaxPoints <= 0 {
+		assignment.MaxPoints = 100
+	}
+	for _, format := range a.Formats {
+		switch format {
+		case "text", "html", "url":
+			assignment.AllowText = true
+		case "file":
+			assignment.AllowFile = true
+		}
+	}
+	if !assignment.AllowText && !assignment.AllowFile {
+		assignment.AllowText = true
+		assignment.AllowFile = true
+	}
+	return assignment
+}
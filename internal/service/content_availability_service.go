@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"sort"
+	"time"
+)
+
+const availabilityTimeLayout = "02 Jan 2006 15:04"
+
+func validateAvailabilityWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return errors.New("jadwal tidak valid: available_until harus setelah available_from")
+	}
+	return nil
+}
+
+func validateModuleInputs(modules []ModuleInput) error {
+	for _, m := range modules {
+		if err := validateAvailabilityWindow(m.AvailableFrom, m.AvailableUntil); err != nil {
+			return err
+		}
+		for _, mat := range m.Materials {
+			if err := validateMaterialInput(mat); err != nil {
+				return err
+			}
+			if err := validateAvailabilityWindow(mat.AvailableFrom, mat.AvailableUntil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// windowLockReason mengembalikan alasan terkunci jika now berada di luar jadwal rilis.
+func windowLockReason(from, until *time.Time, now time.Time) string {
+	if from != nil && now.Before(*from) {
+		return "dibuka mulai " + from.Format(availabilityTimeLayout)
+	}
+	if until != nil && now.After(*until) {
+		return "sudah ditutup sejak " + until.Format(availabilityTimeLayout)
+	}
+	return ""
+}
+
+// applyContentAvailability menandai modul dan materi yang terkunci bagi mahasiswa dan
+// mengosongkan isi materi tersebut. Modul diurutkan berdasarkan Order. Materi yang jadwalnya
+// sudah ditutup tidak dihitung saat memeriksa prasyarat modul sebelumnya.
+func applyContentAvailability(course *model.Course, completedMap map[uint64]bool, now time.Time) {
+	modules := course.Modules
+	sort.SliceStable(modules, func(i, j int) bool {
+		if modules[i].Order != modules[j].Order {
+			return modules[i].Order < modules[j].Order
+		}
+		return modules[i].ID < modules[j].ID
+	})
+
+	previousComplete := true
+	for i := range modules {
+		module := &modules[i]
+
+		reason := windowLockReason(module.AvailableFrom, module.AvailableUntil, now)
+		if reason == "" && module.RequiresPrevious && i > 0 && !previousComplete {
+			reason = "selesaikan modul \"" + modules[i-1].Title + "\" terlebih dahulu"
+		}
+		if reason != "" {
+			module.IsLocked = true
+			module.LockReason = reason
+		}
+
+		complete := true
+		for j := range module.Materials {
+			material := &module.Materials[j]
+
+			closed := material.AvailableUntil != nil && now.After(*material.AvailableUntil)
+			if !closed && !completedMap[material.ID] {
+				complete = false
+			}
+
+			materialReason := reason
+			if materialReason == "" {
+				materialReason = windowLockReason(material.AvailableFrom, material.AvailableUntil, now)
+			}
+			if materialReason != "" {
+				lockMaterial(material, materialReason)
+			}
+		}
+		previousComplete = complete
+	}
+}
+
+func lockMaterial(material *model.Material, reason string) {
+	material.IsLocked = true
+	material.LockReason = reason
+	material.SourceURL = ""
+	material.RawContent = ""
+	material.AltText = ""
+	material.Transcript = ""
+	material.SmartFeature = nil
+}
+
+// materialLockReason mengembalikan alasan materi terkunci bagi studentID, atau "" jika tersedia.
+// course harus memuat Modules.Materials.
+func materialLockReason(course *model.Course, materialID, studentID uint64) (string, error) {
+	completedMap, err := repository.GetCompletedMaterialsMap(course.ID, studentID)
+	if err != nil {
+		return "", err
+	}
+
+	applyContentAvailability(course, completedMap, time.Now())
+	for _, module := range course.Modules {
+		for _, material := range module.Materials {
+			if material.ID == materialID {
+				return material.LockReason, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// ensureMaterialAvailable menolak akses mahasiswa ke materi yang belum dirilis, sudah ditutup,
+// atau prasyaratnya belum terpenuhi.
+func ensureMaterialAvailable(materialID, courseID, studentID uint64) error {
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return errors.New("kelas tidak ditemukan")
+	}
+	return authorizeStudentMaterial(course, materialID, studentID)
+}
@@ -25,6 +25,11 @@ type MaterialInput struct {
 	RawContent  string             `json:"raw_content"`
 	DurationMin int                `json:"duration_min"`
 	HasCaptions bool               `json:"has_captions"`
+	AltText     string             `json:"alt_text"`   // Wajib untuk image, atau untuk slides jika transcript kosong
+	Transcript  string             `json:"transcript"` // Wajib untuk audio; catatan pembicara untuk slides
+
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
 }
 
 type ModuleInput struct {
@@ -32,6 +37,10 @@ type ModuleInput struct {
 	Title     string          `json:"title" binding:"required"`
 	Order     int             `json:"order"`
 	Materials []MaterialInput `json:"materials,omitempty"`
+
+	AvailableFrom    *time.Time `json:"available_from"`
+	AvailableUntil   *time.Time `json:"available_until"`
+	RequiresPrevious bool       `json:"requires_previous"`
 }
 
 type CourseInput struct {
@@ -49,8 +58,9 @@ type Flashcard struct {
 }
 
 type CourseMembersResponse struct {
-	Lecturer model.User   `json:"lecturer"`
-	Students []model.User `json:"students"`
+	Lecturer model.User          `json:"lecturer"`
+	Staff    []model.CourseStaff `json:"staff"`
+	Students []model.User        `json:"students"`
 }
 
 func CreateCourse(input CourseInput, teacherID uint64) (*model.Course, error) {
@@ -58,28 +68,46 @@ func CreateCourse(input CourseInput, teacherID uint64) (*model.Course, error) {
 		input.ClassCode = generateClassCode()
 	}
 
-	status := "draft"
+	status := model.CourseStatusDraft
 	if input.Status != "" {
 		status = input.Status
 	}
+	if status != model.CourseStatusDraft && status != model.CourseStatusPublished {
+		return nil, errors.New("status kelas tidak valid (pilih 'draft' atau 'published')")
+	}
+	if err := validateModuleInputs(input.Modules); err != nil {
+		return nil, err
+	}
 
 	var modules []model.Module
 	for _, m := range input.Modules {
 		var materials []model.Material
-		for _, mat := range m.Materials {
-			materials = append(materials, model.Material{
-				Title:       mat.Title,
-				Type:        mat.Type,
-				SourceURL:   mat.SourceURL,
-				RawContent:  mat.RawContent,
-				DurationMin: mat.DurationMin,
-				HasCaptions: mat.HasCaptions,
-			})
+		for j, mat := range m.Materials {
+			material := model.Material{
+				Order:          j + 1,
+				Title:          mat.Title,
+				Type:           mat.Type,
+				SourceURL:      mat.SourceURL,
+				RawContent:     mat.RawContent,
+				DurationMin:    mat.DurationMin,
+				HasCaptions:    mat.HasCaptions,
+				AltText:        mat.AltText,
+				Transcript:     mat.Transcript,
+				AvailableFrom:  mat.AvailableFrom,
+				AvailableUntil: mat.AvailableUntil,
+			}
+			if err := applyMaterialMedia(&material); err != nil {
+				return nil, err
+			}
+			materials = append(materials, material)
 		}
 		modules = append(modules, model.Module{
-			Title:     m.Title,
-			Order:     m.Order,
-			Materials: materials,
+			Title:            m.Title,
+			Order:            m.Order,
+			AvailableFrom:    m.AvailableFrom,
+			AvailableUntil:   m.AvailableUntil,
+			RequiresPrevious: m.RequiresPrevious,
+			Materials:        materials,
 		})
 	}
 
@@ -91,6 +119,8 @@ func CreateCourse(input CourseInput, teacherID uint64) (*model.Course, error) {
 		ClassCode:   input.ClassCode,
 		Status:      status,
 		Modules:     modules,
+
+		EnrollmentPolicy: model.EnrollmentOpen,
 	}
 
 	if err := repository.CreateCourse(course); err != nil {
@@ -109,25 +139,38 @@ func GetCourseDetail(id uint64) (*model.Course, error) {
 }
 
 func UpdateCourse(id uint64, input CourseInput, teacherID uint64) (*model.Course, error) {
+	if err := authorize(teacherID, PermCourseEdit, id); err != nil {
+		return nil, err
+	}
+
 	existingCourse, err := repository.GetCourseByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if existingCourse.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: you do not own this course")
+	if existingCourse.Status == model.CourseStatusArchived {
+		return nil, errArchivedCourse
+	}
+	if err := validateModuleInputs(input.Modules); err != nil {
+		return nil, err
 	}
 
 	// Prepare the new state
 	course := &model.Course{
 		ID:          id,
-		TeacherID:   teacherID,
+		TeacherID:   existingCourse.TeacherID,
 		Title:       input.Title,
 		Description: input.Description,
 		Thumbnail:   existingCourse.Thumbnail, // Default to existing
 		ClassCode:   existingCourse.ClassCode, // Default to existing
 		Status:      existingCourse.Status,    // Default to existing
+		ArchivedAt:  existingCourse.ArchivedAt,
 		CreatedAt:   existingCourse.CreatedAt,
+
+		// Pengaturan pendaftaran diubah lewat UpdateEnrollmentSettings
+		EnrollmentPolicy:   existingCourse.EnrollmentPolicy,
+		MaxStudents:        existingCourse.MaxStudents,
+		ClassCodeExpiresAt: existingCourse.ClassCodeExpiresAt,
 	}
 
 	if input.Thumbnail != "" {
@@ -137,36 +180,95 @@ func UpdateCourse(id uint64, input CourseInput, teacherID uint64) (*model.Course
 		course.ClassCode = input.ClassCode
 	}
 	if input.Status != "" {
+		if err := validateCourseStatusTransition(existingCourse.Status, input.Status); err != nil {
+			return nil, err
+		}
+		if input.Status == model.CourseStatusArchived {
+			return nil, errors.New("status kelas tidak valid: gunakan endpoint status untuk mengarsipkan kelas")
+		}
 		course.Status = input.Status
 	}
 
+	existingMaterials := make(map[uint64]model.Material)
+	knownVersions := make(map[uint64]int)
+	for _, m := range existingCourse.Modules {
+		for _, mat := range m.Materials {
+			existingMaterials[mat.ID] = mat
+			knownVersions[mat.ID] = mat.ContentVersion
+		}
+	}
+
 	// Map Modules from Input
 	var modules []model.Module
+	var revisions []model.MaterialVersion
 	for _, m := range input.Modules {
 		var materials []model.Material
-		for _, mat := range m.Materials {
-			materials = append(materials, model.Material{
-				ID:          mat.ID,
-				ModuleID:    m.ID, // Will be 0 if new module
-				Title:       mat.Title,
-				Type:        mat.Type,
-				SourceURL:   mat.SourceURL,
-				RawContent:  mat.RawContent,
-				DurationMin: mat.DurationMin,
-				HasCaptions: mat.HasCaptions,
-			})
+		for j, mat := range m.Materials {
+			material := model.Material{
+				ID:             mat.ID,
+				ModuleID:       m.ID,  // Will be 0 if new module
+				Order:          j + 1, // Urutan mengikuti posisi di array
+				Title:          mat.Title,
+				Type:           mat.Type,
+				SourceURL:      mat.SourceURL,
+				RawContent:     mat.RawContent,
+				DurationMin:    mat.DurationMin,
+				HasCaptions:    mat.HasCaptions,
+				AltText:        mat.AltText,
+				Transcript:     mat.Transcript,
+				AvailableFrom:  mat.AvailableFrom,
+				AvailableUntil: mat.AvailableUntil,
+				ContentVersion: 1,
+			}
+
+			var previousMaterial *model.Material
+			if previous, ok := existingMaterials[mat.ID]; ok {
+				previousMaterial = &previous
+			}
+			if err := refreshMaterialMedia(previousMaterial, &material); err != nil {
+				return nil, err
+			}
+
+			// Pertahankan nomor versi; isi yang berubah dicatat sebagai versi baru.
+			if previous, ok := existingMaterials[mat.ID]; ok {
+				material.ContentVersion = previous.ContentVersion
+				if materialContentChanged(&previous, &material) {
+					if previous.ContentVersion < 1 {
+						previous.ContentVersion = 1
+					}
+					material.ContentVersion = previous.ContentVersion + 1
+					revisions = append(revisions,
+						materialVersionSnapshot(&previous, nil, ""),
+						materialVersionSnapshot(&material, &teacherID, ""))
+				}
+			}
+			materials = append(materials, material)
 		}
 		modules = append(modules, model.Module{
-			ID:        m.ID,
-			CourseID:  id,
-			Title:     m.Title,
-			Order:     m.Order,
-			Materials: materials,
+			ID:               m.ID,
+			CourseID:         id,
+			Title:            m.Title,
+			Order:            m.Order,
+			AvailableFrom:    m.AvailableFrom,
+			AvailableUntil:   m.AvailableUntil,
+			RequiresPrevious: m.RequiresPrevious,
+			Materials:        materials,
 		})
 	}
 	course.Modules = modules
 
-	if err := repository.UpdateCourse(course); err != nil {
+	// Materi baru mendapat versi 1 setelah ID-nya terbentuk.
+	versions := func() []model.MaterialVersion {
+		for _, m := range course.Modules {
+			for i := range m.Materials {
+				if _, ok := existingMaterials[m.Materials[i].ID]; !ok {
+					revisions = append(revisions, materialVersionSnapshot(&m.Materials[i], &teacherID, ""))
+				}
+			}
+		}
+		return revisions
+	}
+	if err := repository.UpdateCourse(course, knownVersions, versions); err != nil {
 		return nil, err
 	}
 
@@ -175,17 +277,34 @@ func UpdateCourse(id uint64, input CourseInput, teacherID uint64) (*model.Course
 	return updatedCourse, err
 }
 
-func DeleteCourse(id uint64, teacherID uint64) error {
+func DeleteCourse(id uint64, teacherID uint64, meta SessionMeta) error {
+	if err := authorize(teacherID, PermCourseDelete, id); err != nil {
+		return err
+	}
+
 	course, err := repository.GetCourseByID(id)
 	if err != nil {
-		return err
+		return errors.New("kelas tidak ditemukan")
 	}
 
-	if course.TeacherID != teacherID {
-		return errors.New("unauthorized: you do not own this course")
+	if err := repository.DeleteCourse(id); err != nil {
+		return err
 	}
 
-	return repository.DeleteCourse(id)
+	recordAudit(teacherID, meta, auditEntry{
+		Action:     model.AuditCourseDelete,
+		TargetType: "course",
+		TargetID:   id,
+		CourseID:   &id,
+		Before: map[string]interface{}{
+			"title":      course.Title,
+			"class_code": course.ClassCode,
+			"status":     course.Status,
+			"teacher_id": course.TeacherID,
+		},
+	})
+
+	return nil
 }
 
 func generateClassCode() string {
@@ -201,124 +320,136 @@ func generateClassCode() string {
 	return string(b)
 }
 
-func JoinCourse(classCode string, studentID uint64) error {
+// JoinCourse memproses kode kelas. Pada kelas dengan kebijakan approval, hasilnya adalah
+// permintaan bergabung yang menunggu persetujuan pengajar.
+func JoinCourse(classCode string, studentID uint64) (*JoinCourseResult, error) {
 	course, err := repository.GetCourseByClassCode(classCode)
 	if err != nil {
-		return errors.New("kelas tidak ditemukan")
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	if course.Status == model.CourseStatusArchived {
+		return nil, errors.New("kelas sudah diarsipkan dan tidak menerima mahasiswa baru")
+	}
+
+	if course.ClassCodeExpiresAt != nil && time.Now().After(*course.ClassCodeExpiresAt) {
+		return nil, errors.New("kode kelas sudah kadaluarsa, minta kode baru ke pengajar")
 	}
 
 	exists, err := repository.IsStudentInCourse(course.ID, studentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if exists {
-		return errors.New("anda sudah bergabung di kelas ini")
+		return nil, errors.New("anda sudah bergabung di kelas ini")
 	}
 
 	if course.TeacherID == studentID {
-		return errors.New("anda adalah pengajar di kelas ini")
+		return nil, errors.New("anda adalah pengajar di kelas ini")
 	}
 
-	return repository.AddStudentToCourse(course.ID, studentID)
-}
-
-func GetStudentCourses(studentID uint64) ([]model.Course, error) {
-	return repository.GetCoursesByStudentID(studentID)
-}
+	if _, err := repository.FindCourseStaff(course.ID, studentID); err == nil {
+		return nil, errors.New("anda adalah pengajar di kelas ini")
+	}
 
-func DeleteModule(moduleID uint64, teacherID uint64) error {
-	module, err := repository.GetModuleByID(moduleID)
-	if err != nil {
-		return errors.New("modul tidak ditemukan")
+	if err := ensureCourseCapacity(course); err != nil {
+		return nil, err
 	}
 
-	course, err := repository.GetCourseByID(module.CourseID)
-	if err != nil {
-		return errors.New("kelas tidak ditemukan")
+	if course.EnrollmentPolicy == model.EnrollmentApproval {
+		return requestEnrollment(course, studentID)
 	}
 
-	if course.TeacherID != teacherID {
-		return errors.New("unauthorized: anda tidak memiliki akses ke modul ini")
+	if err := repository.AddStudentWithinCapacity(course.ID, studentID); err != nil {
+		return nil, err
 	}
 
-	return repository.DeleteModule(moduleID)
+	return &JoinCourseResult{CourseID: course.ID, Status: JoinStatusJoined}, nil
 }
 
-func DeleteMaterial(materialID uint64, teacherID uint64) error {
-	material, err := repository.GetMaterialByID(materialID)
-	if err != nil {
-		return errors.New("materi tidak ditemukan")
-	}
+func GetStudentCourses(studentID uint64, status string) ([]model.Course, error) {
+	return repository.GetCoursesByStudentID(studentID, status)
+}
 
-	module, err := repository.GetModuleByID(material.ModuleID)
-	if err != nil {
-		return errors.New("modul tidak ditemukan (data inkonsisten)")
+func DeleteModule(moduleID uint64, teacherID uint64) error {
+	if err := authorize(teacherID, PermModuleEdit, moduleID); err != nil {
+		return err
 	}
 
-	course, err := repository.GetCourseByID(module.CourseID)
-	if err != nil {
-		return errors.New("kelas tidak ditemukan (data inkonsisten)")
-	}
+	return repository.DeleteModule(moduleID)
+}
 
-	if course.TeacherID != teacherID {
-		return errors.New("unauthorized: anda tidak memiliki akses ke materi ini")
+func DeleteMaterial(materialID uint64, teacherID uint64) error {
+	if err := authorize(teacherID, PermMaterialEdit, materialID); err != nil {
+		return err
 	}
 
 	return repository.DeleteMaterial(materialID)
 }
 
 func CreateMaterial(moduleID uint64, input MaterialInput, teacherID uint64) (*model.Material, error) {
-	module, err := repository.GetModuleByID(moduleID)
-	if err != nil {
-		return nil, errors.New("modul tidak ditemukan")
+	if err := authorize(teacherID, PermModuleEdit, moduleID); err != nil {
+		return nil, err
 	}
-
-	course, err := repository.GetCourseByID(module.CourseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
+	if err := validateMaterialInput(input); err != nil {
+		return nil, err
+	}
+	if err := validateAvailabilityWindow(input.AvailableFrom, input.AvailableUntil); err != nil {
+		return nil, err
 	}
 
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke modul ini")
+	order, err := repository.NextMaterialOrder(moduleID)
+	if err != nil {
+		return nil, err
 	}
 
 	material := &model.Material{
-		ModuleID:    moduleID,
-		Title:       input.Title,
-		Type:        input.Type,
-		SourceURL:   input.SourceURL,
-		RawContent:  input.RawContent,
-		DurationMin: input.DurationMin,
-		HasCaptions: input.HasCaptions,
+		ModuleID:       moduleID,
+		Order:          order,
+		Title:          input.Title,
+		Type:           input.Type,
+		SourceURL:      input.SourceURL,
+		RawContent:     input.RawContent,
+		DurationMin:    input.DurationMin,
+		HasCaptions:    input.HasCaptions,
+		AltText:        input.AltText,
+		Transcript:     input.Transcript,
+		AvailableFrom:  input.AvailableFrom,
+		AvailableUntil: input.AvailableUntil,
+	}
+	if err := applyMaterialMedia(material); err != nil {
+		return nil, err
 	}
 
 	if err := repository.CreateMaterial(material); err != nil {
 		return nil, err
 	}
+	if err := repository.CreateMaterialVersions([]model.MaterialVersion{
+		materialVersionSnapshot(material, &teacherID, ""),
+	}); err != nil {
+		return nil, err
+	}
 
 	return material, nil
 }
 
 func UpdateMaterial(materialID uint64, input MaterialInput, teacherID uint64) (*model.Material, error) {
-	material, err := repository.GetMaterialByID(materialID)
-	if err != nil {
-		return nil, errors.New("materi tidak ditemukan")
+	if err := authorize(teacherID, PermMaterialEdit, materialID); err != nil {
+		return nil, err
 	}
-
-	module, err := repository.GetModuleByID(material.ModuleID)
-	if err != nil {
-		return nil, errors.New("modul tidak ditemukan")
+	if err := validateMaterialInput(input); err != nil {
+		return nil, err
 	}
-
-	course, err := repository.GetCourseByID(module.CourseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
+	if err := validateAvailabilityWindow(input.AvailableFrom, input.AvailableUntil); err != nil {
+		return nil, err
 	}
 
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke materi ini")
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
 	}
 
+	previous := *material
 	material.Title = input.Title
 	material.Type = input.Type
 
@@ -329,15 +460,33 @@ func UpdateMaterial(materialID uint64, input MaterialInput, teacherID uint64) (*
 	material.RawContent = input.RawContent
 	material.DurationMin = input.DurationMin
 	material.HasCaptions = input.HasCaptions
+	material.AltText = input.AltText
+	material.Transcript = input.Transcript
+	material.AvailableFrom = input.AvailableFrom
+	material.AvailableUntil = input.AvailableUntil
+	if err := refreshMaterialMedia(&previous, material); err != nil {
+		return nil, err
+	}
+
+	if !materialContentChanged(&previous, material) {
+		if err := repository.UpdateMaterial(material); err != nil {
+			return nil, err
+		}
+		return material, nil
+	}
 
-	if err := repository.UpdateMaterial(material); err != nil {
+	if err := saveMaterialRevision(previous, material, teacherID, ""); err != nil {
 		return nil, err
 	}
 
-	return material, nil
+	return repository.GetMaterialByID(materialID)
 }
 
 func ToggleMaterialCompletion(userID, materialID uint64) (bool, error) {
+	if err := authorize(userID, PermMaterialComplete, materialID); err != nil {
+		return false, err
+	}
+
 	completed, err := repository.ToggleMaterialCompletion(userID, materialID)
 	if err != nil {
 		return false, err
@@ -345,33 +494,41 @@ func ToggleMaterialCompletion(userID, materialID uint64) (bool, error) {
 
 	// If marked as completed (true), record activity
 	if completed {
-		material, err := repository.GetMaterialByID(materialID)
-		if err == nil {
-			// Find courseID via Module
-			module, err := repository.GetModuleByID(material.ModuleID)
-			if err == nil {
-				user, _ := repository.FindUserByID(userID)
-				userName := "Mahasiswa"
-				if user != nil {
-					userName = user.Name
-				}
-
-				activity := &model.Activity{
-					UserID:      userID,
-					CourseID:    module.CourseID,
-					Type:        model.ActivityTypeMaterial,
-					Title:       "Menyelesaikan Materi",
-					Description: fmt.Sprintf("%s menyelesaikan materi: %s", userName, material.Title),
-					RelatedID:   materialID,
-				}
-				repository.CreateActivity(activity)
-			}
-		}
+		recordMaterialCompletedActivity(userID, materialID)
 	}
 
 	return completed, nil
 }
 
+// recordMaterialCompletedActivity mencatat aktivitas "Menyelesaikan Materi" untuk dashboard pengajar.
+func recordMaterialCompletedActivity(userID, materialID uint64) {
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return
+	}
+	// Find courseID via Module
+	module, err := repository.GetModuleByID(material.ModuleID)
+	if err != nil {
+		return
+	}
+
+	user, _ := repository.FindUserByID(userID)
+	userName := "Mahasiswa"
+	if user != nil {
+		userName = user.Name
+	}
+
+	activity := &model.Activity{
+		UserID:      userID,
+		CourseID:    module.CourseID,
+		Type:        model.ActivityTypeMaterial,
+		Title:       "Menyelesaikan Materi",
+		Description: fmt.Sprintf("%s menyelesaikan materi: %s", userName, material.Title),
+		RelatedID:   materialID,
+	}
+	repository.CreateActivity(activity)
+}
+
 func GetStudentCourseDetail(courseID, studentID uint64) (*model.Course, error) {
 	// 1. Check if student is enrolled
 	inCourse, err := repository.IsStudentInCourse(courseID, studentID)
@@ -382,6 +539,12 @@ func GetStudentCourseDetail(courseID, studentID uint64) (*model.Course, error) {
 		return nil, errors.New("unauthorized: anda belum bergabung di kelas ini")
 	}
 
+	return studentCourseView(courseID, studentID, studentAccessibilityProfile(studentID))
+}
+
+// studentCourseView menyusun detail kelas seperti yang dilihat mahasiswa, termasuk status
+// penyelesaian materi. Dipakai juga oleh mode pratinjau dengan previewStudentID.
+func studentCourseView(courseID, studentID uint64, profile *model.AccessibilityProfile) (*model.Course, error) {
 	// 2. Get Course Detail
 	course, err := repository.GetCourseByID(courseID)
 	if err != nil {
@@ -403,6 +566,16 @@ func GetStudentCourseDetail(courseID, studentID uint64) (*model.Course, error) {
 		}
 	}
 
+	// 5. Apply release schedule & prerequisites
+	applyContentAvailability(course, completedMap, time.Now())
+
+	// 6. Published announcements, delivered for the student's accessibility profile
+	announcements, err := deliverCourseAnnouncements(courseID, studentID, profile)
+	if err != nil {
+		return nil, err
+	}
+	course.Announcements = announcements
+
 	return course, nil
 }
 
@@ -418,9 +591,6 @@ func GetMaterialDetailWithStatus(materialID, userID uint64) (*model.Material, er
 	}
 
 	// Verify enrollment (or ownership)
-	// If teacher is the same as userID?
-	// But usually this view is for students.
-	// For teachers, they can view it too?
 	// Let's assume Student context for now since "WithStatus" implies student progress.
 	// We check enrollment.
 	inCourse, err := repository.IsStudentInCourse(module.CourseID, userID)
@@ -428,33 +598,39 @@ func GetMaterialDetailWithStatus(materialID, userID uint64) (*model.Material, er
 		return nil, err
 	}
 
-	// If not student, check if teacher
+	// If not student, check if teaching staff (or admin)
 	if !inCourse {
-		course, err := repository.GetCourseByID(module.CourseID)
-		if err == nil && course.TeacherID == userID {
-			// Is teacher, allowed. Status logic might be different or irrelevant (always false or true?)
-			// Let's just return material without IsCompleted (default false)
-			return material, nil
+		if err := authorize(userID, PermMaterialView, materialID); err != nil {
+			return nil, err
 		}
-		// Not teacher either
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke materi ini")
+		// Is teacher, allowed. Return material without IsCompleted (default false)
+		return material, nil
+	}
+
+	// Is Student, check release schedule & prerequisites
+	if err := ensureMaterialAvailable(materialID, module.CourseID, userID); err != nil {
+		return nil, err
 	}
 
-	// Is Student, check completion
+	// Check completion
 	isCompleted := repository.GetMaterialCompletionStatus(userID, materialID)
 	material.IsCompleted = isCompleted
 
+	// Last position for resuming (heartbeat progress)
+	if progress, err := repository.FindMaterialProgress(userID, materialID); err == nil {
+		material.Progress = progress
+	}
+
 	return material, nil
 }
 
 func CreateStudentAndEnroll(courseID uint64, input CreateStudentInput, teacherID uint64) (*model.User, error) {
 	// 1. Verify Teacher owns the course
-	course, err := repository.GetCourseByID(courseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
+	if err := authorize(teacherID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
 	}
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
 	}
 
 	// 2. Create Student Account
@@ -483,7 +659,7 @@ func GenerateMaterialSummary(materialID uint64) (*model.SmartFeature, error) {
 	// Check if summary already exists (simple caching)
 	// You might want to allow re-generation, but strictly sticking to 'generate if needed' for cost efficiency first.
 	// If the user wants to regenerate, we can clear this field or add a force flag.
-	if material.SmartFeature != nil && material.SmartFeature.Summary != "" {
+	if material.SmartFeature != nil && material.SmartFeature.Summary != "" && !material.SmartFeature.IsStale {
 		return material.SmartFeature, nil
 	}
 
@@ -510,9 +686,10 @@ func GenerateMaterialSummary(materialID uint64) (*model.SmartFeature, error) {
 
 	// Return ephemeral result (not saved yet)
 	return &model.SmartFeature{
-		MaterialID:  materialID,
-		Summary:     summary,
-		IsGenerated: true, // Marked as AI generated
+		MaterialID:    materialID,
+		Summary:       summary,
+		IsGenerated:   true, // Marked as AI generated
+		SourceVersion: material.ContentVersion,
 	}, nil
 }
 
@@ -531,6 +708,8 @@ func SaveMaterialSummary(materialID uint64, summary string) (*model.SmartFeature
 
 	smartFeature.Summary = summary
 	smartFeature.IsGenerated = true
+	smartFeature.SourceVersion = material.ContentVersion
+	smartFeature.IsStale = false
 
 	if err := repository.SaveSmartFeature(smartFeature); err != nil {
 		return nil, errors.New("gagal menyimpan ringkasan")
@@ -596,6 +775,27 @@ func getMaterialContent(material *model.Material) (string, error) {
 			return "", errors.New("gagal mengambil transkrip Youtube: " + err.Error())
 		}
 		return transcript, nil
+	} else if material.Type == model.TypeAudio {
+		if material.Transcript == "" {
+			return "", errors.New("transkrip audio kosong")
+		}
+		return material.Transcript, nil
+	} else if material.Type == model.TypeImage || material.Type == model.TypeSlides {
+		var parts []string
+		if material.Type == model.TypeSlides && material.Media.MimeType == "application/pdf" {
+			if extracted, err := utils.ExtractTextFromPDF(material.SourceURL); err == nil && strings.TrimSpace(extracted) != "" {
+				if len(extracted) > 200000 {
+					extracted = extracted[:200000]
+				}
+				parts = append(parts, extracted)
+			}
+		}
+		for _, text := range []string{material.AltText, material.Transcript, material.RawContent} {
+			if strings.TrimSpace(text) != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n\n"), nil
 	}
 	return "", errors.New("tipe materi ini belum didukung untuk fitur AI")
 }
@@ -737,12 +937,11 @@ MATERI:
 
 func ImportStudentsToCourseFromExcel(courseID uint64, teacherID uint64, filePath string) (map[string]interface{}, error) {
 	// 1. Verify Course Ownership
-	course, err := repository.GetCourseByID(courseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
+	if err := authorize(teacherID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
 	}
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
 	}
 
 	// 2. Open Excel
@@ -829,12 +1028,8 @@ func ImportStudentsToCourseFromExcel(courseID uint64, teacherID uint64, filePath
 
 func GetCourseStudents(courseID uint64, teacherID uint64) ([]model.User, error) {
 	// 1. Verify Course Ownership
-	course, err := repository.GetCourseByID(courseID)
-	if err != nil {
-		return nil, errors.New("kelas tidak ditemukan")
-	}
-	if course.TeacherID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak memiliki akses ke kelas ini")
+	if err := authorize(teacherID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
 	}
 
 	// 2. Get Students
@@ -849,14 +1044,8 @@ func GetCourseMembers(courseID uint64, userID uint64) (*CourseMembersResponse, e
 	}
 
 	// 2. Access Control: User must be teacher or student in course
-	if course.TeacherID != userID {
-		isStudent, err := repository.IsStudentInCourse(courseID, userID)
-		if err != nil {
-			return nil, err
-		}
-		if !isStudent {
-			return nil, errors.New("unauthorized: anda bukan anggota kelas ini")
-		}
+	if err := authorize(userID, PermCourseView, courseID); err != nil {
+		return nil, err
 	}
 
 	// 3. Get Lecturer
@@ -865,7 +1054,13 @@ func GetCourseMembers(courseID uint64, userID uint64) (*CourseMembersResponse, e
 		return nil, errors.New("data pengajar tidak ditemukan")
 	}
 
-	// 4. Get Students
+	// 4. Get Co-teachers & TAs
+	staff, err := repository.GetCourseStaff(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Get Students
 	students, err := repository.GetStudentsByCourseID(courseID)
 	if err != nil {
 		return nil, err
@@ -873,6 +1068,7 @@ func GetCourseMembers(courseID uint64, userID uint64) (*CourseMembersResponse, e
 
 	return &CourseMembersResponse{
 		Lecturer: *lecturer,
+		Staff:    staff,
 		Students: students,
 	}, nil
 }
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+)
+
+type AddCourseStaffInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type UpdateCourseStaffInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func parseStaffRole(role string) (model.CourseStaffRole, error) {
+	switch role {
+	case "co_teacher", "co-teacher":
+		return model.StaffRoleCoTeacher, nil
+	case "ta", "asisten":
+		return model.StaffRoleTA, nil
+	}
+	return "", errors.New("role staf tidak valid (pilih 'co_teacher' atau 'ta')")
+}
+
+func GetCourseStaff(courseID uint64, userID uint64) ([]model.CourseStaff, error) {
+	if err := authorize(userID, PermCourseTeach, courseID); err != nil {
+		return nil, err
+	}
+
+	staff, err := repository.GetCourseStaff(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if staff == nil {
+		staff = []model.CourseStaff{}
+	}
+	return staff, nil
+}
+
+// AddCourseStaff menambahkan dosen lain sebagai co-teacher atau asisten (TA) di kelas.
+// Hanya pemilik kelas (atau admin) yang boleh mengatur staf.
+func AddCourseStaff(courseID uint64, input AddCourseStaffInput, ownerID uint64, meta SessionMeta) (*model.CourseStaff, error) {
+	if err := authorize(ownerID, PermCourseManageStaff, courseID); err != nil {
+		return nil, err
+	}
+
+	role, err := parseStaffRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := repository.FindUserByEmail(input.Email)
+	if err != nil {
+		return nil, errors.New("user dengan email tersebut tidak ditemukan")
+	}
+
+	if user.Role != model.RoleLecturer {
+		return nil, errors.New("role tidak valid: hanya akun dosen yang dapat ditambahkan sebagai pengajar")
+	}
+
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	if course.TeacherID == user.ID {
+		return nil, errors.New("user sudah menjadi pemilik kelas ini")
+	}
+
+	if _, err := repository.FindCourseStaff(courseID, user.ID); err == nil {
+		return nil, errors.New("user sudah menjadi pengajar di kelas ini")
+	}
+
+	isStudent, err := repository.IsStudentInCourse(courseID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if isStudent {
+		return nil, errors.New("user sudah terdaftar sebagai mahasiswa di kelas ini")
+	}
+
+	member := &model.CourseStaff{
+		CourseID: courseID,
+		UserID:   user.ID,
+		Role:     role,
+	}
+	if err := repository.AddCourseStaff(member); err != nil {
+		return nil, err
+	}
+	member.User = user
+
+	recordAudit(ownerID, meta, auditEntry{
+		Action:     model.AuditCourseStaffAdd,
+		TargetType: "user",
+		TargetID:   user.ID,
+		CourseID:   &courseID,
+		After:      map[string]interface{}{"role": role},
+	})
+
+	return member, nil
+}
+
+func UpdateCourseStaffRole(courseID uint64, userID uint64, input UpdateCourseStaffInput, ownerID uint64, meta SessionMeta) error {
+	if err := authorize(ownerID, PermCourseManageStaff, courseID); err != nil {
+		return err
+	}
+
+	role, err := parseStaffRole(input.Role)
+	if err != nil {
+		return err
+	}
+
+	member, err := repository.FindCourseStaff(courseID, userID)
+	if err != nil {
+		return errors.New("staf pengajar tidak ditemukan")
+	}
+
+	if err := repository.UpdateCourseStaffRole(courseID, userID, role); err != nil {
+		return err
+	}
+
+	recordAudit(ownerID, meta, auditEntry{
+		Action:     model.AuditCourseStaffUpdate,
+		TargetType: "user",
+		TargetID:   userID,
+		CourseID:   &courseID,
+		Before:     map[string]interface{}{"role": member.Role},
+		After:      map[string]interface{}{"role": role},
+	})
+
+	return nil
+}
+
+func RemoveCourseStaff(courseID uint64, userID uint64, ownerID uint64, meta SessionMeta) error {
+	if err := authorize(ownerID, PermCourseManageStaff, courseID); err != nil {
+		return err
+	}
+
+	member, err := repository.FindCourseStaff(courseID, userID)
+	if err != nil {
+		return errors.New("staf pengajar tidak ditemukan")
+	}
+
+	if err := repository.RemoveCourseStaff(courseID, userID); err != nil {
+		return err
+	}
+
+	recordAudit(ownerID, meta, auditEntry{
+		Action:     model.AuditCourseStaffRemove,
+		TargetType: "user",
+		TargetID:   userID,
+		CourseID:   &courseID,
+		Before:     map[string]interface{}{"role": member.Role},
+	})
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"time"
+)
+
+// Diawali "unauthorized" agar handler memetakannya ke 403.
+var errArchivedCourse = errors.New("unauthorized: kelas sudah diarsipkan dan hanya bisa dilihat")
+
+// courseStatusTransitions adalah perpindahan status yang diizinkan. Kelas archived bisa
+// dipulihkan ke published; kelas yang sudah published tidak bisa kembali ke draft.
+var courseStatusTransitions = map[string][]string{
+	model.CourseStatusDraft:     {model.CourseStatusPublished},
+	model.CourseStatusPublished: {model.CourseStatusArchived},
+	model.CourseStatusArchived:  {model.CourseStatusPublished},
+}
+
+type CourseStatusInput struct {
+	Status string `json:"status" binding:"required,oneof=draft published archived"`
+}
+
+func validateCourseStatusTransition(from, to string) error {
+	if from == "" {
+		from = model.CourseStatusDraft
+	}
+	if from == to {
+		return nil
+	}
+	for _, allowed := range courseStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return errors.New("perubahan status kelas tidak valid: " + from + " -> " + to)
+}
+
+// ensureCourseWritable menolak perubahan pada kelas yang sudah diarsipkan.
+func ensureCourseWritable(courseID uint64) error {
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return errors.New("kelas tidak ditemukan")
+	}
+	if course.Status == model.CourseStatusArchived {
+		return errArchivedCourse
+	}
+	return nil
+}
+
+// ChangeCourseStatus memindahkan kelas di siklus draft -> published -> archived.
+func ChangeCourseStatus(courseID uint64, input CourseStatusInput, userID uint64, meta SessionMeta) (*model.Course, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	previousStatus := course.Status
+	if err := validateCourseStatusTransition(previousStatus, input.Status); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{"status": input.Status}
+	if input.Status == model.CourseStatusArchived {
+		fields["archived_at"] = time.Now()
+	} else {
+		fields["archived_at"] = nil
+	}
+	if err := repository.UpdateCourseFields(courseID, fields); err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, meta, auditEntry{
+		Action:     model.AuditCourseStatusChange,
+		TargetType: "course",
+		TargetID:   courseID,
+		CourseID:   &courseID,
+		Before:     map[string]interface{}{"status": previousStatus},
+		After:      map[string]interface{}{"status": input.Status},
+	})
+
+	return repository.GetCourseByID(courseID)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"ramah-disabilitas-be/internal/model"
+	"testing"
+)
+
+func TestValidateCourseStatusTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		wantErr  bool
+	}{
+		{model.CourseStatusDraft, model.CourseStatusPublished, false},
+		{"", model.CourseStatusPublished, false},
+		{model.CourseStatusPublished, model.CourseStatusArchived, false},
+		{model.CourseStatusArchived, model.CourseStatusPublished, false},
+		{model.CourseStatusDraft, model.CourseStatusDraft, false},
+		{"", model.CourseStatusDraft, false},
+		{model.CourseStatusPublished, model.CourseStatusPublished, false},
+		{model.CourseStatusDraft, model.CourseStatusArchived, true},
+		{"", model.CourseStatusArchived, true},
+		{model.CourseStatusPublished, model.CourseStatusDraft, true},
+		{model.CourseStatusArchived, model.CourseStatusDraft, true},
+		{model.CourseStatusDraft, "deleted", true},
+	}
+
+	for _, tt := range tests {
+		err := validateCourseStatusTransition(tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateCourseStatusTransition(%q, %q) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type CloneCourseInput struct {
+	Title              string `json:"title"`                // Kosong = "<judul asal> (Salinan)"
+	DeadlineOffsetDays int    `json:"deadline_offset_days"` // Geser semua deadline tugas, boleh negatif
+}
+
+type PublishTemplateInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=private public"`
+}
+
+type InstantiateTemplateInput struct {
+	Title     string     `json:"title"`
+	StartDate *time.Time `json:"start_date"` // Tanggal mulai kelas baru, default sekarang
+}
+
+// courseSnapshot adalah isi kelas yang disalin. Deadline tugas disimpan relatif terhadap
+// tanggal mulai kelas sumber (CreatedAt), sehingga bisa dipasang ulang ke tanggal mulai baru.
+type courseSnapshot struct {
+	Modules     []moduleSnapshot     `json:"modules"`
+	Assignments []assignmentSnapshot `json:"assignments"`
+}
+
+// Jadwal rilis modul dan materi juga disimpan sebagai offset (detik) dari tanggal mulai kelas.
+type moduleSnapshot struct {
+	Title                string             `json:"title"`
+	Order                int                `json:"order"`
+	AvailableFromOffset  *int64             `json:"available_from_offset_seconds,omitempty"`
+	AvailableUntilOffset *int64             `json:"available_until_offset_seconds,omitempty"`
+	RequiresPrevious     bool               `json:"requires_previous"`
+	Materials            []materialSnapshot `json:"materials"`
+}
+
+type materialSnapshot struct {
+	Title        string                `json:"title"`
+	Order        int                   `json:"order"`
+	Type         model.MaterialType    `json:"type"`
+	SourceURL    string                `json:"source_url"`
+	RawContent   string                `json:"raw_content"`
+	DurationMin  int                   `json:"duration_min"`
+	HasCaptions  bool                  `json:"has_captions"`
+	AltText      string                `json:"alt_text,omitempty"`
+	Transcript   string                `json:"transcript,omitempty"`
+	Media        model.MediaMetadata   `json:"media"`
+	SmartFeature *smartFeatureSnapshot `json:"smart_feature,omitempty"`
+
+	AvailableFromOffset  *int64 `json:"available_from_offset_seconds,omitempty"`
+	AvailableUntilOffset *int64 `json:"available_until_offset_seconds,omitempty"`
+}
+
+type smartFeatureSnapshot struct {
+	Summary     string         `json:"summary"`
+	Simplified  string         `json:"simplified_content"`
+	QuizData    datatypes.JSON `json:"quiz_data"`
+	IsGenerated bool           `json:"is_generated"`
+	IsStale     bool           `json:"is_stale"` // Ringkasan dibuat dari versi materi yang lebih lama
+}
+
+type assignmentSnapshot struct {
+	Title          string `json:"title"`
+	Instruction    string `json:"instruction"`
+	DeadlineOffset int64  `json:"deadline_offset_seconds"`
+	ModuleIndex    int    `json:"module_index"` // -1 jika tidak terkait modul
+	MaxPoints      int    `json:"max_points"`
+	AllowText      bool   `json:"allow_text"`
+	AllowFile      bool   `json:"allow_file"`
+	AllowVoice     bool   `json:"allow_voice"`
+	AllowLate      bool   `json:"allow_late"`
+}
+
+func offsetFrom(t *time.Time, start time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	offset := int64(t.Sub(start).Seconds())
+	return &offset
+}
+
+func timeFromOffset(offset *int64, start time.Time) *time.Time {
+	if offset == nil {
+		return nil
+	}
+	t := start.Add(time.Duration(*offset) * time.Second)
+	return &t
+}
+
+func snapshotCourse(course *model.Course) courseSnapshot {
+	snapshot := courseSnapshot{
+		Modules:     []moduleSnapshot{},
+		Assignments: []assignmentSnapshot{},
+	}
+
+	moduleIndex := make(map[uint64]int)
+	for i, module := range course.Modules {
+		moduleIndex[module.ID] = i
+
+		ms := moduleSnapshot{
+			Title:                module.Title,
+			Order:                module.Order,
+			AvailableFromOffset:  offsetFrom(module.AvailableFrom, course.CreatedAt),
+			AvailableUntilOffset: offsetFrom(module.AvailableUntil, course.CreatedAt),
+			RequiresPrevious:     module.RequiresPrevious,
+			Materials:            []materialSnapshot{},
+		}
+		for _, material := range module.Materials {
+			mat := materialSnapshot{
+				Title:       material.Title,
+				Order:       material.Order,
+				Type:        material.Type,
+				SourceURL:   material.SourceURL,
+				RawContent:  material.RawContent,
+				DurationMin: material.DurationMin,
+				HasCaptions: material.HasCaptions,
+				AltText:     material.AltText,
+				Transcript:  material.Transcript,
+				Media:       material.Media,
+
+				AvailableFromOffset:  offsetFrom(material.AvailableFrom, course.CreatedAt),
+				AvailableUntilOffset: offsetFrom(material.AvailableUntil, course.CreatedAt),
+			}
+			if sf := material.SmartFeature; sf != nil {
+				mat.SmartFeature = &smartFeatureSnapshot{
+					Summary:     sf.Summary,
+					Simplified:  sf.Simplified,
+					QuizData:    sf.QuizData,
+					IsGenerated: sf.IsGenerated,
+					IsStale:     sf.IsStale,
+				}
+			}
+			ms.Materials = append(ms.Materials, mat)
+		}
+		snapshot.Modules = append(snapshot.Modules, ms)
+	}
+
+	for _, assignment := range course.Assignments {
+		as := assignmentSnapshot{
+			Title:          assignment.Title,
+			Instruction:    assignment.Instruction,
+			DeadlineOffset: int64(assignment.Deadline.Sub(course.CreatedAt).Seconds()),
+			ModuleIndex:    -1,
+			MaxPoints:      assignment.MaxPoints,
+			AllowText:      assignment.AllowText,
+			AllowFile:      assignment.AllowFile,
+			AllowVoice:     assignment.AllowVoice,
+			AllowLate:      assignment.AllowLate,
+		}
+		if assignment.ModuleID != nil {
+			if idx, ok := moduleIndex[*assignment.ModuleID]; ok {
+				as.ModuleIndex = idx
+			}
+		}
+		snapshot.Assignments = append(snapshot.Assignments, as)
+	}
+
+	return snapshot
+}
+
+// createCourseFromSnapshot membuat kelas draft baru milik teacherID. Deadline tugas dan jadwal
+// rilis = startDate + offset.
+func createCourseFromSnapshot(base model.Course, snapshot courseSnapshot, startDate time.Time, teacherID uint64) (*model.Course, error) {
+	code, err := generateUniqueClassCode()
+	if err != nil {
+		return nil, err
+	}
+
+	course := &model.Course{
+		TeacherID:        teacherID,
+		Title:            base.Title,
+		Description:      base.Description,
+		Thumbnail:        base.Thumbnail,
+		ClassCode:        code,
+		Status:           "draft",
+		EnrollmentPolicy: base.EnrollmentPolicy,
+		MaxStudents:      base.MaxStudents,
+	}
+	if course.EnrollmentPolicy == "" {
+		course.EnrollmentPolicy = model.EnrollmentOpen
+	}
+
+	for _, ms := range snapshot.Modules {
+		module := model.Module{
+			Title:            ms.Title,
+			Order:            ms.Order,
+			AvailableFrom:    timeFromOffset(ms.AvailableFromOffset, startDate),
+			AvailableUntil:   timeFromOffset(ms.AvailableUntilOffset, startDate),
+			RequiresPrevious: ms.RequiresPrevious,
+		}
+		for _, mat := range ms.Materials {
+			material := model.Material{
+				Title:       mat.Title,
+				Order:       mat.Order,
+				Type:        mat.Type,
+				SourceURL:   mat.SourceURL,
+				RawContent:  mat.RawContent,
+				DurationMin: mat.DurationMin,
+				HasCaptions: mat.HasCaptions,
+				AltText:     mat.AltText,
+				Transcript:  mat.Transcript,
+				Media:       mat.Media,
+
+				AvailableFrom:  timeFromOffset(mat.AvailableFromOffset, startDate),
+				AvailableUntil: timeFromOffset(mat.AvailableUntilOffset, startDate),
+			}
+			if sf := mat.SmartFeature; sf != nil {
+				material.SmartFeature = &model.SmartFeature{
+					Summary:     sf.Summary,
+					Simplified:  sf.Simplified,
+					QuizData:    sf.QuizData,
+					IsGenerated: sf.IsGenerated,
+					IsStale:     sf.IsStale,
+				}
+			}
+			module.Materials = append(module.Materials, material)
+		}
+		course.Modules = append(course.Modules, module)
+	}
+
+	assignments := make([]model.Assignment, 0, len(snapshot.Assignments))
+	moduleIndex := make([]int, 0, len(snapshot.Assignments))
+	for _, as := range snapshot.Assignments {
+		assignments = append(assignments, model.Assignment{
+			Title:       as.Title,
+			Instruction: as.Instruction,
+			Deadline:    startDate.Add(time.Duration(as.DeadlineOffset) * time.Second),
+			MaxPoints:   as.MaxPoints,
+			AllowText:   as.AllowText,
+			AllowFile:   as.AllowFile,
+			AllowVoice:  as.AllowVoice,
+			AllowLate:   as.AllowLate,
+		})
+		moduleIndex = append(moduleIndex, as.ModuleIndex)
+	}
+
+	if err := repository.CreateCourseCopy(course, assignments, moduleIndex); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// CloneCourse menyalin kelas beserta modul, materi, ringkasan AI dan tugas menjadi kelas draft
+// baru milik user. Mahasiswa, staf, pengumpulan dan progres tidak ikut disalin.
+func CloneCourse(courseID uint64, input CloneCourseInput, userID uint64) (*model.Course, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+
+	source, err := repository.GetCourseForCopy(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	base := *source
+	base.Title = input.Title
+	if base.Title == "" {
+		base.Title = source.Title + " (Salinan)"
+	}
+
+	startDate := source.CreatedAt.AddDate(0, 0, input.DeadlineOffsetDays)
+	return createCourseFromSnapshot(base, snapshotCourse(source), startDate, userID)
+}
+
+// PublishCourseTemplate menyimpan isi kelas saat ini ke pustaka template.
+func PublishCourseTemplate(courseID uint64, input PublishTemplateInput, userID uint64) (*model.CourseTemplate, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+
+	source, err := repository.GetCourseForCopy(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	snapshot := snapshotCourse(source)
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	materialCount := 0
+	for _, module := range snapshot.Modules {
+		materialCount += len(module.Materials)
+	}
+
+	template := &model.CourseTemplate{
+		AuthorID:        userID,
+		SourceCourseID:  &courseID,
+		Title:           input.Title,
+		Description:     input.Description,
+		Thumbnail:       source.Thumbnail,
+		Visibility:      model.TemplateVisibility(input.Visibility),
+		Content:         content,
+		ModuleCount:     len(snapshot.Modules),
+		MaterialCount:   materialCount,
+		AssignmentCount: len(snapshot.Assignments),
+	}
+	if template.Title == "" {
+		template.Title = source.Title
+	}
+	if template.Description == "" {
+		template.Description = source.Description
+	}
+	if template.Visibility == "" {
+		template.Visibility = model.TemplatePrivate
+	}
+
+	if err := repository.CreateCourseTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func GetCourseTemplates(userID uint64, search string) ([]model.CourseTemplate, error) {
+	templates, err := repository.GetVisibleCourseTemplates(userID, search)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []model.CourseTemplate{}
+	}
+	return templates, nil
+}
+
+func GetCourseTemplateDetail(templateID uint64, userID uint64) (*model.CourseTemplate, error) {
+	template, err := repository.FindCourseTemplateByID(templateID)
+	if err != nil {
+		return nil, errors.New("template tidak ditemukan")
+	}
+
+	if template.Visibility != model.TemplatePublic && template.AuthorID != userID && !isAdmin(userID, "") {
+		return nil, errors.New("template tidak ditemukan")
+	}
+
+	return template, nil
+}
+
+func DeleteCourseTemplate(templateID uint64, userID uint64) error {
+	template, err := repository.FindCourseTemplateByID(templateID)
+	if err != nil {
+		return errors.New("template tidak ditemukan")
+	}
+
+	if template.AuthorID != userID && !isAdmin(userID, "") {
+		return errors.New("unauthorized: hanya pembuat template yang dapat menghapusnya")
+	}
+
+	return repository.DeleteCourseTemplate(templateID)
+}
+
+// InstantiateCourseTemplate membuat kelas draft baru dari template. Deadline tugas dihitung
+// dari StartDate dengan jarak yang sama seperti di kelas sumber.
+func InstantiateCourseTemplate(templateID uint64, input InstantiateTemplateInput, userID uint64) (*model.Course, error) {
+	template, err := GetCourseTemplateDetail(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot courseSnapshot
+	if err := json.Unmarshal(template.Content, &snapshot); err != nil {
+		return nil, errors.New("isi template rusak dan tidak dapat dipakai")
+	}
+
+	base := model.Course{
+		Title:       input.Title,
+		Description: template.Description,
+		Thumbnail:   template.Thumbnail,
+	}
+	if base.Title == "" {
+		base.Title = template.Title
+	}
+
+	startDate := time.Now()
+	if input.StartDate != nil {
+		startDate = *input.StartDate
+	}
+
+	return createCourseFromSnapshot(base, snapshot, startDate, userID)
+}
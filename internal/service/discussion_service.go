@@ -0,0 +1,364 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+	"time"
+)
+
+type ThreadInput struct {
+	Title      string   `json:"title" binding:"required,max=255"`
+	Body       string   `json:"body" binding:"required"`
+	MentionIDs []uint64 `json:"mention_ids"` // User yang disebut (anggota kelas)
+}
+
+type ReplyInput struct {
+	Body         string   `json:"body"`
+	ParentID     *uint64  `json:"parent_id"`      // Balasan atas balasan lain
+	VoiceNoteURL string   `json:"voice_note_url"` // URL hasil POST /upload (khusus tuna wicara / tuna daksa)
+	Transcript   string   `json:"transcript"`
+	MentionIDs   []uint64 `json:"mention_ids"`
+}
+
+type ThreadModerationInput struct {
+	Value bool `json:"value"` // true = kunci/sembunyikan, false = buka/tampilkan kembali
+}
+
+type MentionReadInput struct {
+	IDs []uint64 `json:"ids"` // Kosong = tandai semua
+}
+
+// isCourseStaff mengecek apakah user melihat diskusi sebagai pengajar (termasuk admin).
+func isCourseStaff(userID, courseID uint64) bool {
+	return authorize(userID, PermCourseTeach, courseID) == nil
+}
+
+// buildMentions memastikan setiap user yang disebut adalah anggota kelas. Penulis sendiri dan
+// ID ganda diabaikan.
+func buildMentions(courseID uint64, ids []uint64, authorID uint64) ([]model.DiscussionMention, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+
+	seen := map[uint64]bool{authorID: true}
+	var mentions []model.DiscussionMention
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		relation, err := resolveCourseRelation(id, course)
+		if err != nil {
+			return nil, err
+		}
+		if relation == RelationNone {
+			return nil, errors.New("input diskusi tidak valid: user yang disebut bukan anggota kelas")
+		}
+		mentions = append(mentions, model.DiscussionMention{UserID: id, MentionedByID: authorID})
+	}
+	return mentions, nil
+}
+
+func CreateCourseThread(courseID uint64, input ThreadInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermCourseView, courseID); err != nil {
+		return nil, err
+	}
+	return createThread(courseID, nil, input, userID)
+}
+
+func CreateMaterialThread(materialID uint64, input ThreadInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermMaterialView, materialID); err != nil {
+		return nil, err
+	}
+	courseID, err := resolveCourseID(ResourceMaterial, materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	return createThread(courseID, &materialID, input, userID)
+}
+
+func createThread(courseID uint64, materialID *uint64, input ThreadInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	body := strings.TrimSpace(input.Body)
+	if title == "" || body == "" {
+		return nil, errors.New("input diskusi tidak valid: judul dan isi wajib diisi")
+	}
+	mentions, err := buildMentions(courseID, input.MentionIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	thread := &model.DiscussionThread{
+		CourseID:       courseID,
+		MaterialID:     materialID,
+		AuthorID:       userID,
+		Title:          title,
+		Body:           body,
+		LastActivityAt: now,
+	}
+	if err := repository.CreateDiscussionThread(thread, mentions); err != nil {
+		return nil, err
+	}
+
+	return repository.FindDiscussionThreadByID(thread.ID)
+}
+
+// GetCourseThreads mengembalikan thread di kelas. materialID opsional untuk menyaring per materi;
+// generalOnly hanya mengambil diskusi umum. Thread tersembunyi hanya terlihat oleh pengajar.
+func GetCourseThreads(courseID uint64, materialID *uint64, generalOnly bool, userID uint64) ([]model.DiscussionThread, error) {
+	if err := authorize(userID, PermCourseView, courseID); err != nil {
+		return nil, err
+	}
+	if materialID != nil {
+		if err := authorize(userID, PermMaterialView, *materialID); err != nil {
+			return nil, err
+		}
+	}
+	return listThreads(courseID, repository.DiscussionFilter{
+		MaterialID:    materialID,
+		GeneralOnly:   generalOnly,
+		IncludeHidden: isCourseStaff(userID, courseID),
+	})
+}
+
+func GetMaterialThreads(materialID uint64, userID uint64) ([]model.DiscussionThread, error) {
+	if err := authorize(userID, PermMaterialView, materialID); err != nil {
+		return nil, err
+	}
+	courseID, err := resolveCourseID(ResourceMaterial, materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	return listThreads(courseID, repository.DiscussionFilter{
+		MaterialID:    &materialID,
+		IncludeHidden: isCourseStaff(userID, courseID),
+	})
+}
+
+func listThreads(courseID uint64, filter repository.DiscussionFilter) ([]model.DiscussionThread, error) {
+	threads, err := repository.GetDiscussionThreads(courseID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if threads == nil {
+		threads = []model.DiscussionThread{}
+	}
+	return threads, nil
+}
+
+// findVisibleThread memuat thread yang boleh dilihat user: thread tersembunyi dan thread pada
+// materi yang masih terkunci tidak terlihat oleh mahasiswa.
+func findVisibleThread(threadID, userID uint64) (*model.DiscussionThread, bool, error) {
+	thread, err := repository.FindDiscussionThreadByID(threadID)
+	if err != nil {
+		return nil, false, errors.New("diskusi tidak ditemukan")
+	}
+	staff := isCourseStaff(userID, thread.CourseID)
+	if thread.IsHidden && !staff {
+		return nil, false, errors.New("diskusi tidak ditemukan")
+	}
+	if thread.MaterialID != nil {
+		if err := authorize(userID, PermMaterialView, *thread.MaterialID); err != nil {
+			return nil, false, err
+		}
+	}
+	return thread, staff, nil
+}
+
+func GetThreadDetail(threadID uint64, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermThreadView, threadID); err != nil {
+		return nil, err
+	}
+	_, staff, err := findVisibleThread(threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := repository.GetDiscussionThreadWithReplies(threadID, staff)
+	if err != nil {
+		return nil, errors.New("diskusi tidak ditemukan")
+	}
+	if thread.Replies == nil {
+		thread.Replies = []model.DiscussionReply{}
+	}
+	return thread, nil
+}
+
+// CreateReply menambahkan balasan. Balasan berupa voice note hanya untuk user dengan profil
+// tuna wicara atau tuna daksa; isi teks boleh kosong jika ada voice note.
+func CreateReply(threadID uint64, input ReplyInput, userID uint64) (*model.DiscussionReply, error) {
+	if err := authorize(userID, PermThreadReply, threadID); err != nil {
+		return nil, err
+	}
+	thread, _, err := findVisibleThread(threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if thread.IsLocked {
+		return nil, errors.New("unauthorized: diskusi ini sudah dikunci")
+	}
+
+	reply := &model.DiscussionReply{
+		ThreadID:   threadID,
+		AuthorID:   userID,
+		Body:       strings.TrimSpace(input.Body),
+		Transcript: strings.TrimSpace(input.Transcript),
+		CreatedAt:  time.Now(),
+	}
+
+	if input.ParentID != nil {
+		parent, err := repository.FindDiscussionReplyByID(*input.ParentID)
+		if err != nil || parent.ThreadID != threadID || parent.IsHidden {
+			return nil, errors.New("input diskusi tidak valid: balasan induk tidak ditemukan di diskusi ini")
+		}
+		reply.ParentID = input.ParentID
+	}
+
+	if voiceURL := strings.TrimSpace(input.VoiceNoteURL); voiceURL != "" {
+		duration, err := validateVoiceNote(voiceURL, userID)
+		if err != nil {
+			return nil, err
+		}
+		reply.VoiceNoteURL = voiceURL
+		reply.VoiceDurationSec = duration
+	} else if reply.Transcript != "" {
+		return nil, errors.New("input diskusi tidak valid: transkrip hanya untuk balasan voice note")
+	}
+	if reply.Body == "" && reply.VoiceNoteURL == "" {
+		return nil, errors.New("input diskusi tidak valid: isi balasan wajib diisi")
+	}
+
+	mentions, err := buildMentions(thread.CourseID, input.MentionIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.CreateDiscussionReply(reply, mentions); err != nil {
+		return nil, err
+	}
+
+	return repository.FindDiscussionReplyByID(reply.ID)
+}
+
+// validateVoiceNote memeriksa profil aksesibilitas penulis dan memastikan file benar-benar audio.
+// Mengembalikan durasi (detik) bila terbaca.
+func validateVoiceNote(voiceURL string, userID uint64) (int, error) {
+	profile, err := repository.FindAccessibilityProfileByUserID(userID)
+	if err != nil || (!profile.SpeechImpaired && !profile.PhysicalImpaired) {
+		return 0, errors.New("unauthorized: balasan voice note hanya tersedia untuk pengguna dengan profil tuna wicara atau tuna daksa")
+	}
+
+	info, err := utils.ProbeMedia(voiceURL)
+	if err != nil {
+		return 0, errors.New("input diskusi tidak valid: voice note tidak dapat dibaca")
+	}
+	if !mimeMatchesMaterialType(model.TypeAudio, info.MimeType) {
+		return 0, errors.New("input diskusi tidak valid: voice note harus berupa file audio")
+	}
+	return info.DurationSec, nil
+}
+
+func moderationFields(value bool, field string, userID uint64) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		field:             value,
+		"moderated_by_id": userID,
+		"moderated_at":    &now,
+	}
+}
+
+func LockThread(threadID uint64, input ThreadModerationInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermThreadModerate, threadID); err != nil {
+		return nil, err
+	}
+	if err := repository.UpdateDiscussionThreadFields(threadID, moderationFields(input.Value, "is_locked", userID)); err != nil {
+		return nil, err
+	}
+	return repository.FindDiscussionThreadByID(threadID)
+}
+
+func HideThread(threadID uint64, input ThreadModerationInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermThreadModerate, threadID); err != nil {
+		return nil, err
+	}
+	if err := repository.UpdateDiscussionThreadFields(threadID, moderationFields(input.Value, "is_hidden", userID)); err != nil {
+		return nil, err
+	}
+	return repository.FindDiscussionThreadByID(threadID)
+}
+
+// HideReply menyembunyikan balasan dari mahasiswa. Balasan yang disembunyikan tidak bisa tetap
+// menjadi jawaban thread.
+func HideReply(replyID uint64, input ThreadModerationInput, userID uint64) (*model.DiscussionReply, error) {
+	if err := authorize(userID, PermReplyModerate, replyID); err != nil {
+		return nil, err
+	}
+	reply, err := repository.FindDiscussionReplyByID(replyID)
+	if err != nil {
+		return nil, errors.New("balasan diskusi tidak ditemukan")
+	}
+
+	if err := repository.UpdateDiscussionReplyFields(replyID, moderationFields(input.Value, "is_hidden", userID)); err != nil {
+		return nil, err
+	}
+	if input.Value && reply.IsAnswer {
+		if err := repository.SetDiscussionAnswer(reply.ThreadID, nil); err != nil {
+			return nil, err
+		}
+	}
+	return repository.FindDiscussionReplyByID(replyID)
+}
+
+// MarkReplyAnswer menandai (atau membatalkan) balasan sebagai jawaban thread. Satu thread hanya
+// punya satu jawaban; menandai balasan lain menggantikan jawaban sebelumnya.
+func MarkReplyAnswer(replyID uint64, input ThreadModerationInput, userID uint64) (*model.DiscussionThread, error) {
+	if err := authorize(userID, PermReplyModerate, replyID); err != nil {
+		return nil, err
+	}
+	reply, err := repository.FindDiscussionReplyByID(replyID)
+	if err != nil {
+		return nil, errors.New("balasan diskusi tidak ditemukan")
+	}
+
+	var answerID *uint64
+	if input.Value {
+		if reply.IsHidden {
+			return nil, errors.New("input diskusi tidak valid: balasan tersembunyi tidak dapat dijadikan jawaban")
+		}
+		answerID = &reply.ID
+	} else if !reply.IsAnswer {
+		return repository.GetDiscussionThreadWithReplies(reply.ThreadID, true)
+	}
+
+	if err := repository.SetDiscussionAnswer(reply.ThreadID, answerID); err != nil {
+		return nil, err
+	}
+	return repository.GetDiscussionThreadWithReplies(reply.ThreadID, true)
+}
+
+func GetMyMentions(userID uint64, unreadOnly bool) ([]model.DiscussionMention, error) {
+	mentions, err := repository.GetDiscussionMentions(userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	if mentions == nil {
+		mentions = []model.DiscussionMention{}
+	}
+	return mentions, nil
+}
+
+func MarkMentionsRead(input MentionReadInput, userID uint64) error {
+	return repository.MarkDiscussionMentionsRead(userID, input.IDs)
+}
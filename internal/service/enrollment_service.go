@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"time"
+)
+
+const (
+	JoinStatusJoined  = "joined"
+	JoinStatusPending = "pending"
+)
+
+type JoinCourseResult struct {
+	CourseID uint64 `json:"course_id"`
+	Status   string `json:"status"` // joined, pending
+}
+
+type EnrollmentSettingsInput struct {
+	EnrollmentPolicy   string     `json:"enrollment_policy" binding:"required,oneof=open approval"`
+	MaxStudents        int        `json:"max_students" binding:"min=0"`
+	ClassCodeExpiresAt *time.Time `json:"class_code_expires_at"`
+}
+
+type RegenerateClassCodeInput struct {
+	ExpiresAt *time.Time `json:"expires_at"` // Kosong = kode tidak kadaluarsa
+}
+
+// ensureCourseCapacity menolak lebih awal pendaftaran ke kelas yang sudah penuh (mis. sebelum
+// membuat permintaan bergabung). Batas yang mengikat dicek ulang oleh repository saat insert.
+func ensureCourseCapacity(course *model.Course) error {
+	if course.MaxStudents <= 0 {
+		return nil
+	}
+
+	count, err := repository.CountStudentsInCourse(course.ID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(course.MaxStudents) {
+		return repository.ErrCourseFull
+	}
+	return nil
+}
+
+func requestEnrollment(course *model.Course, studentID uint64) (*JoinCourseResult, error) {
+	request, err := repository.FindEnrollmentRequest(course.ID, studentID)
+	if err == nil && request.Status == model.EnrollmentPending {
+		return nil, errors.New("permintaan bergabung anda masih menunggu persetujuan pengajar")
+	}
+	if err != nil {
+		request = &model.EnrollmentRequest{CourseID: course.ID, UserID: studentID}
+	}
+
+	request.Status = model.EnrollmentPending
+	request.DecidedByID = nil
+	request.DecidedAt = nil
+	if err := repository.SaveEnrollmentRequest(request); err != nil {
+		return nil, err
+	}
+
+	return &JoinCourseResult{CourseID: course.ID, Status: JoinStatusPending}, nil
+}
+
+func GetMyEnrollmentRequests(studentID uint64) ([]model.EnrollmentRequest, error) {
+	requests, err := repository.GetEnrollmentRequestsByUser(studentID)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []model.EnrollmentRequest{}
+	}
+	return requests, nil
+}
+
+// LeaveCourse mengeluarkan mahasiswa dari kelas. Progres dan pengumpulan tugas tetap disimpan,
+// sehingga kembali utuh jika mahasiswa bergabung lagi.
+func LeaveCourse(courseID uint64, studentID uint64) error {
+	inCourse, err := repository.IsStudentInCourse(courseID, studentID)
+	if err != nil {
+		return err
+	}
+	if !inCourse {
+		return errors.New("unauthorized: anda belum bergabung di kelas ini")
+	}
+
+	return repository.RemoveStudentFromCourse(courseID, studentID)
+}
+
+func UpdateEnrollmentSettings(courseID uint64, input EnrollmentSettingsInput, userID uint64) (*model.Course, error) {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+
+	if input.ClassCodeExpiresAt != nil && input.ClassCodeExpiresAt.Before(time.Now()) {
+		return nil, errors.New("tanggal kadaluarsa kode kelas tidak valid: harus di masa depan")
+	}
+
+	if err := repository.UpdateCourseFields(courseID, map[string]interface{}{
+		"enrollment_policy":     model.EnrollmentPolicy(input.EnrollmentPolicy),
+		"max_students":          input.MaxStudents,
+		"class_code_expires_at": input.ClassCodeExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return repository.GetCourseByID(courseID)
+}
+
+// RegenerateClassCode mengganti kode kelas. Kode lama langsung tidak bisa dipakai lagi.
+func RegenerateClassCode(courseID uint64, input RegenerateClassCodeInput, userID uint64) (*model.Course, error) {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+
+	if input.ExpiresAt != nil && input.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("tanggal kadaluarsa kode kelas tidak valid: harus di masa depan")
+	}
+
+	code, err := generateUniqueClassCode()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.UpdateCourseFields(courseID, map[string]interface{}{
+		"class_code":            code,
+		"class_code_expires_at": input.ExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return repository.GetCourseByID(courseID)
+}
+
+func generateUniqueClassCode() (string, error) {
+	for i := 0; i < 5; i++ {
+		code := generateClassCode()
+		if _, err := repository.GetCourseByClassCode(code); err != nil {
+			return code, nil
+		}
+	}
+	return "", errors.New("gagal membuat kode kelas unik, silahkan coba lagi")
+}
+
+func GetEnrollmentRequests(courseID uint64, status string, userID uint64) ([]model.EnrollmentRequest, error) {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+
+	requests, err := repository.GetEnrollmentRequestsByCourse(courseID, status)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []model.EnrollmentRequest{}
+	}
+	return requests, nil
+}
+
+func ApproveEnrollmentRequest(courseID uint64, requestID uint64, userID uint64) (*model.EnrollmentRequest, error) {
+	request, err := pendingEnrollmentRequest(courseID, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+	if course.Status == model.CourseStatusArchived {
+		return nil, errArchivedCourse
+	}
+
+	if err := repository.DecideEnrollmentRequest(request, model.EnrollmentApproved, userID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func RejectEnrollmentRequest(courseID uint64, requestID uint64, userID uint64) (*model.EnrollmentRequest, error) {
+	request, err := pendingEnrollmentRequest(courseID, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.DecideEnrollmentRequest(request, model.EnrollmentRejected, userID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func pendingEnrollmentRequest(courseID uint64, requestID uint64, userID uint64) (*model.EnrollmentRequest, error) {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+
+	request, err := repository.FindEnrollmentRequestByID(requestID)
+	if err != nil || request.CourseID != courseID {
+		return nil, errors.New("permintaan bergabung tidak ditemukan")
+	}
+	if request.Status != model.EnrollmentPending {
+		return nil, errors.New("permintaan bergabung sudah diproses")
+	}
+	return request, nil
+}
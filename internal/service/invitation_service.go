@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+	"time"
+)
+
+const courseInvitationTTL = 7 * 24 * time.Hour
+
+type InviteStudentsInput struct {
+	Emails []string `json:"emails" binding:"required,min=1,max=100,dive,email"`
+}
+
+type InvitationResult struct {
+	Email  string `json:"email"`
+	Status string `json:"status"` // sent, resent, already_member, failed
+	Error  string `json:"error,omitempty"`
+}
+
+// InvitationInfo ditampilkan di halaman undangan sebelum user login atau mendaftar.
+type InvitationInfo struct {
+	CourseID    uint64    `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	InvitedBy   string    `json:"invited_by"`
+	Email       string    `json:"email"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	HasAccount  bool      `json:"has_account"` // true: login lalu terima, false: daftar lewat undangan
+}
+
+type AcceptInvitationInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type RegisterWithInvitationInput struct {
+	Token           string `json:"token" binding:"required"`
+	Name            string `json:"name" binding:"required"`
+	Password        string `json:"password" binding:"required,min=6"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
+}
+
+// InviteStudentsToCourse mengirim undangan ke setiap email. Undangan yang masih menunggu untuk
+// email yang sama dikirim ulang dengan tautan baru (tautan lama tidak berlaku).
+func InviteStudentsToCourse(courseID uint64, input InviteStudentsInput, inviterID uint64) ([]InvitationResult, error) {
+	if err := authorize(inviterID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("kelas tidak ditemukan")
+	}
+	if course.Status == model.CourseStatusArchived {
+		return nil, errArchivedCourse
+	}
+
+	inviterName := "Pengajar"
+	if inviter, err := repository.FindUserByID(inviterID); err == nil {
+		inviterName = inviter.Name
+	}
+
+	results := make([]InvitationResult, 0, len(input.Emails))
+	seen := make(map[string]bool)
+	for _, email := range input.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		result := InvitationResult{Email: email, Status: "sent"}
+
+		if user, err := repository.FindUserByEmailIgnoreCase(email); err == nil {
+			if inCourse, _ := repository.IsStudentInCourse(courseID, user.ID); inCourse {
+				result.Status = "already_member"
+				results = append(results, result)
+				continue
+			}
+		}
+
+		invitation, err := repository.FindPendingCourseInvitation(courseID, email)
+		if err == nil {
+			result.Status = "resent"
+		} else {
+			invitation = &model.CourseInvitation{
+				CourseID: courseID,
+				Email:    email,
+				Status:   model.InvitationPending,
+			}
+		}
+		invitation.InvitedByID = inviterID
+		invitation.TokenID = utils.GenerateRandomToken(16)
+		invitation.ExpiresAt = time.Now().Add(courseInvitationTTL)
+
+		if err := sendCourseInvitation(invitation, course.Title, inviterName); err != nil {
+			log.Printf("Failed to send course invitation to %s: %v\n", email, err)
+			result.Status = "failed"
+			result.Error = "gagal mengirim undangan"
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func sendCourseInvitation(invitation *model.CourseInvitation, courseTitle, inviterName string) error {
+	if err := repository.SaveCourseInvitation(invitation); err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateCourseInviteToken(invitation.ID, invitation.TokenID, invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return utils.SendCourseInvitationEmail(invitation.Email, token, courseTitle, inviterName)
+}
+
+func GetCourseInvitations(courseID uint64, status string, userID uint64) ([]model.CourseInvitation, error) {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return nil, err
+	}
+
+	invitations, err := repository.GetCourseInvitations(courseID, status)
+	if err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []model.CourseInvitation{}
+	}
+	return invitations, nil
+}
+
+func RevokeCourseInvitation(courseID uint64, invitationID uint64, userID uint64) error {
+	if err := authorize(userID, PermCourseManageStudents, courseID); err != nil {
+		return err
+	}
+
+	invitation, err := repository.FindCourseInvitationByID(invitationID)
+	if err != nil || invitation.CourseID != courseID {
+		return errors.New("undangan tidak ditemukan")
+	}
+	if invitation.Status != model.InvitationPending {
+		return errors.New("undangan sudah diterima atau dibatalkan")
+	}
+
+	return repository.UpdateCourseInvitationStatus(invitationID, model.InvitationRevoked)
+}
+
+// loadCourseInvitation memvalidasi token undangan dan memastikan undangan masih berlaku.
+func loadCourseInvitation(token string) (*model.CourseInvitation, error) {
+	invitationID, tokenID, err := utils.ParseCourseInviteToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := repository.FindCourseInvitationByID(invitationID)
+	if err != nil || invitation.TokenID != tokenID {
+		return nil, errors.New("token undangan tidak valid")
+	}
+	if invitation.Status != model.InvitationPending {
+		return nil, errors.New("undangan sudah diterima atau dibatalkan")
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, errors.New("token undangan tidak valid atau sudah kadaluarsa")
+	}
+	return invitation, nil
+}
+
+func GetInvitationInfo(token string) (*InvitationInfo, error) {
+	invitation, err := loadCourseInvitation(token)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &InvitationInfo{
+		CourseID:  invitation.CourseID,
+		Email:     invitation.Email,
+		ExpiresAt: invitation.ExpiresAt,
+	}
+	if invitation.Course != nil {
+		info.CourseTitle = invitation.Course.Title
+	}
+	if invitation.InvitedBy != nil {
+		info.InvitedBy = invitation.InvitedBy.Name
+	}
+	if _, err := repository.FindUserByEmailIgnoreCase(invitation.Email); err == nil {
+		info.HasAccount = true
+	}
+	return info, nil
+}
+
+// AcceptCourseInvitation dipakai user yang sudah login. Email akun harus sama dengan email undangan.
+func AcceptCourseInvitation(token string, userID uint64) (*JoinCourseResult, error) {
+	invitation, err := loadCourseInvitation(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("unauthorized: undangan ini ditujukan untuk email lain")
+	}
+
+	if err := enrollInvitedUser(invitation, user.ID); err != nil {
+		return nil, err
+	}
+
+	return &JoinCourseResult{CourseID: invitation.CourseID, Status: JoinStatusJoined}, nil
+}
+
+// RegisterWithInvitation membuat akun mahasiswa untuk email undangan, memasukkannya ke kelas,
+// lalu langsung membuka sesi. Email dianggap terverifikasi karena tautan diterima di email tersebut.
+func RegisterWithInvitation(input RegisterWithInvitationInput, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	invitation, err := loadCourseInvitation(input.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := repository.FindUserByEmailIgnoreCase(invitation.Email); err == nil {
+		return nil, nil, errors.New("email sudah ada, silahkan login untuk menerima undangan")
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := &model.User{
+		Name:       input.Name,
+		Email:      invitation.Email,
+		Password:   hashedPassword,
+		Role:       model.RoleStudent,
+		IsVerified: true,
+	}
+	if err := repository.CreateUser(user); err != nil {
+		return nil, nil, err
+	}
+
+	if err := enrollInvitedUser(invitation, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	return continueLogin(user, meta)
+}
+
+// enrollInvitedUser memasukkan user ke kelas. Undangan dikirim langsung oleh pengajar, sehingga
+// kebijakan approval dan batas kapasitas kelas tidak berlaku.
+func enrollInvitedUser(invitation *model.CourseInvitation, userID uint64) error {
+	course, err := repository.GetCourseByID(invitation.CourseID)
+	if err != nil {
+		return errors.New("kelas tidak ditemukan")
+	}
+	if course.Status == model.CourseStatusArchived {
+		return errors.New("kelas sudah diarsipkan dan tidak menerima mahasiswa baru")
+	}
+	if course.TeacherID == userID {
+		return errors.New("anda adalah pengajar di kelas ini")
+	}
+	if _, err := repository.FindCourseStaff(course.ID, userID); err == nil {
+		return errors.New("anda adalah pengajar di kelas ini")
+	}
+
+	inCourse, err := repository.IsStudentInCourse(course.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !inCourse {
+		if err := repository.AddStudentToCourse(course.ID, userID); err != nil {
+			return err
+		}
+	}
+
+	return repository.MarkCourseInvitationAccepted(invitation, userID)
+}
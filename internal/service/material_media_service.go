@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+)
+
+var materialUploadExtensions = map[model.MaterialType][]string{
+	model.TypePDF:    {".pdf"},
+	model.TypeAudio:  {".mp3", ".wav", ".m4a", ".ogg"},
+	model.TypeImage:  {".png", ".jpg", ".jpeg", ".gif"},
+	model.TypeSlides: {".pdf", ".png", ".jpg", ".jpeg"},
+}
+
+func isMediaMaterial(t model.MaterialType) bool {
+	return t == model.TypeAudio || t == model.TypeImage || t == model.TypeSlides
+}
+
+// ValidateMaterialUpload dipanggil sebelum file materi disimpan.
+func ValidateMaterialUpload(materialType model.MaterialType, filename string) error {
+	allowed, ok := materialUploadExtensions[materialType]
+	if !ok {
+		return errors.New("file materi tidak valid: tipe " + string(materialType) + " tidak menerima unggahan file")
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, a := range allowed {
+		if ext == a {
+			return nil
+		}
+	}
+	return errors.New("file materi tidak valid: format untuk tipe " + string(materialType) + " harus " + strings.Join(allowed, ", "))
+}
+
+// validateMaterialInput memastikan tipe dikenal dan materi non-teks punya alternatif teks.
+func validateMaterialInput(input MaterialInput) error {
+	switch input.Type {
+	case model.TypeYoutube, model.TypePDF, model.TypeText:
+	case model.TypeAudio:
+		if strings.TrimSpace(input.Transcript) == "" {
+			return errors.New("input materi tidak valid: transkrip wajib diisi untuk materi audio")
+		}
+	case model.TypeImage:
+		if strings.TrimSpace(input.AltText) == "" {
+			return errors.New("input materi tidak valid: alt text wajib diisi untuk materi gambar")
+		}
+	case model.TypeSlides:
+		if strings.TrimSpace(input.AltText) == "" && strings.TrimSpace(input.Transcript) == "" {
+			return errors.New("input materi tidak valid: alt text atau catatan slide (transcript) wajib diisi untuk materi slide")
+		}
+	default:
+		return errors.New("tipe materi tidak valid: pilih youtube, pdf, text, audio, image atau slides")
+	}
+	return nil
+}
+
+func mimeMatchesMaterialType(t model.MaterialType, mime string) bool {
+	switch t {
+	case model.TypeAudio:
+		// m4a terbaca sebagai kontainer MP4, ogg sebagai application/ogg
+		return strings.HasPrefix(mime, "audio/") || mime == "application/ogg" || mime == "video/mp4"
+	case model.TypeImage:
+		return strings.HasPrefix(mime, "image/")
+	case model.TypeSlides:
+		return mime == "application/pdf" || strings.HasPrefix(mime, "image/")
+	}
+	return true
+}
+
+// applyMaterialMedia membaca metadata file materi audio/gambar/slide di server dan menolak file
+// yang isinya tidak sesuai tipe. Durasi audio juga mengisi DurationMin.
+func applyMaterialMedia(material *model.Material) error {
+	if !isMediaMaterial(material.Type) {
+		material.Media = model.MediaMetadata{}
+		return nil
+	}
+	if material.SourceURL == "" {
+		return errors.New("file materi tidak valid: file wajib diunggah untuk materi " + string(material.Type))
+	}
+
+	info, err := utils.ProbeMedia(material.SourceURL)
+	if err != nil {
+		return errors.New("file materi tidak valid: gagal membaca file (" + err.Error() + ")")
+	}
+	if !mimeMatchesMaterialType(material.Type, info.MimeType) {
+		return errors.New("file materi tidak valid: isi file (" + info.MimeType + ") tidak sesuai tipe " + string(material.Type))
+	}
+
+	material.Media = model.MediaMetadata{
+		MimeType:    info.MimeType,
+		FileSize:    info.FileSize,
+		DurationSec: info.DurationSec,
+		Width:       info.Width,
+		Height:      info.Height,
+		PageCount:   info.PageCount,
+	}
+	if material.Type == model.TypeAudio && info.DurationSec > 0 {
+		material.DurationMin = (info.DurationSec + 59) / 60
+	}
+	return nil
+}
+
+// refreshMaterialMedia membaca ulang metadata hanya jika file atau tipe berubah.
+func refreshMaterialMedia(previous, material *model.Material) error {
+	if previous != nil && previous.SourceURL == material.SourceURL && previous.Type == material.Type && previous.Media.MimeType != "" {
+		material.Media = previous.Media
+		if material.Type == model.TypeAudio && previous.Media.DurationSec > 0 {
+			material.DurationMin = previous.DurationMin
+		}
+		return nil
+	}
+	return applyMaterialMedia(material)
+}
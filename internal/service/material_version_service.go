@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"strings"
+)
+
+// Batas ukuran diff baris (jumlah baris lama x baru) agar tabel LCS tidak terlalu besar.
+const maxLineDiffCells = 4_000_000
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"` // equal, add, remove
+	Text string `json:"text"`
+}
+
+type MaterialVersionDiff struct {
+	MaterialID uint64                 `json:"material_id"`
+	From       int                    `json:"from"`
+	To         int                    `json:"to"`
+	Changes    map[string]FieldChange `json:"changes"`
+	RawContent []DiffLine             `json:"raw_content"`
+}
+
+func materialContentChanged(a, b *model.Material) bool {
+	return a.Title != b.Title ||
+		a.Type != b.Type ||
+		a.SourceURL != b.SourceURL ||
+		a.RawContent != b.RawContent ||
+		a.DurationMin != b.DurationMin ||
+		a.HasCaptions != b.HasCaptions ||
+		a.AltText != b.AltText ||
+		a.Transcript != b.Transcript
+}
+
+func materialVersionSnapshot(material *model.Material, editorID *uint64, note string) model.MaterialVersion {
+	version := material.ContentVersion
+	if version < 1 {
+		version = 1
+	}
+	return model.MaterialVersion{
+		MaterialID:  material.ID,
+		Version:     version,
+		Title:       material.Title,
+		Type:        material.Type,
+		SourceURL:   material.SourceURL,
+		RawContent:  material.RawContent,
+		DurationMin: material.DurationMin,
+		HasCaptions: material.HasCaptions,
+		AltText:     material.AltText,
+		Transcript:  material.Transcript,
+		EditedByID:  editorID,
+		Note:        note,
+	}
+}
+
+// saveMaterialRevision menyimpan perubahan isi materi sebagai versi baru. previous adalah keadaan
+// sebelum diubah; dicatat juga jika belum ada di riwayat (materi lama sebelum fitur versi ada).
+func saveMaterialRevision(previous model.Material, material *model.Material, editorID uint64, note string) error {
+	previousVersion := previous.ContentVersion
+	if previous.ContentVersion < 1 {
+		previous.ContentVersion = 1
+	}
+	material.ContentVersion = previous.ContentVersion + 1
+
+	versions := []model.MaterialVersion{
+		materialVersionSnapshot(&previous, nil, ""),
+		materialVersionSnapshot(material, &editorID, note),
+	}
+	return repository.UpdateMaterialWithVersions(material, previousVersion, versions)
+}
+
+func GetMaterialVersions(materialID uint64, userID uint64) ([]model.MaterialVersion, error) {
+	if err := authorize(userID, PermMaterialTeach, materialID); err != nil {
+		return nil, err
+	}
+
+	versions, err := repository.GetMaterialVersions(materialID)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		versions = []model.MaterialVersion{}
+	}
+	return versions, nil
+}
+
+func GetMaterialVersion(materialID uint64, version int, userID uint64) (*model.MaterialVersion, error) {
+	if err := authorize(userID, PermMaterialTeach, materialID); err != nil {
+		return nil, err
+	}
+	return findMaterialVersion(materialID, version)
+}
+
+// findMaterialVersion mengembalikan versi tertentu. Versi yang sedang aktif tetap bisa diambil
+// walau belum pernah dicatat (materi lama yang belum pernah diubah).
+func findMaterialVersion(materialID uint64, version int) (*model.MaterialVersion, error) {
+	if v, err := repository.FindMaterialVersion(materialID, version); err == nil {
+		return v, nil
+	}
+
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	current := materialVersionSnapshot(material, nil, "")
+	if current.Version != version {
+		return nil, errors.New("versi materi tidak ditemukan")
+	}
+	return &current, nil
+}
+
+// DiffMaterialVersions membandingkan dua versi. to = 0 berarti versi yang sedang aktif.
+func DiffMaterialVersions(materialID uint64, from, to int, userID uint64) (*MaterialVersionDiff, error) {
+	if err := authorize(userID, PermMaterialTeach, materialID); err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		material, err := repository.GetMaterialByID(materialID)
+		if err != nil {
+			return nil, errors.New("materi tidak ditemukan")
+		}
+		to = materialVersionSnapshot(material, nil, "").Version
+	}
+
+	oldVersion, err := findMaterialVersion(materialID, from)
+	if err != nil {
+		return nil, err
+	}
+	newVersion, err := findMaterialVersion(materialID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &MaterialVersionDiff{
+		MaterialID: materialID,
+		From:       from,
+		To:         to,
+		Changes:    map[string]FieldChange{},
+		RawContent: diffLines(oldVersion.RawContent, newVersion.RawContent),
+	}
+	addChange := func(field string, a, b interface{}) {
+		if a != b {
+			diff.Changes[field] = FieldChange{From: a, To: b}
+		}
+	}
+	addChange("title", oldVersion.Title, newVersion.Title)
+	addChange("type", oldVersion.Type, newVersion.Type)
+	addChange("source_url", oldVersion.SourceURL, newVersion.SourceURL)
+	addChange("duration_min", oldVersion.DurationMin, newVersion.DurationMin)
+	addChange("has_captions", oldVersion.HasCaptions, newVersion.HasCaptions)
+	addChange("alt_text", oldVersion.AltText, newVersion.AltText)
+	addChange("transcript", oldVersion.Transcript, newVersion.Transcript)
+
+	return diff, nil
+}
+
+// RollbackMaterial mengembalikan isi materi ke versi lama dengan membuat versi baru,
+// sehingga riwayat tidak pernah hilang.
+func RollbackMaterial(materialID uint64, version int, userID uint64) (*model.Material, error) {
+	if err := authorize(userID, PermMaterialEdit, materialID); err != nil {
+		return nil, err
+	}
+
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	target, err := findMaterialVersion(materialID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *material
+	material.Title = target.Title
+	material.Type = target.Type
+	material.SourceURL = target.SourceURL
+	material.RawContent = target.RawContent
+	material.DurationMin = target.DurationMin
+	material.HasCaptions = target.HasCaptions
+	material.AltText = target.AltText
+	material.Transcript = target.Transcript
+
+	if !materialContentChanged(&previous, material) {
+		return nil, errors.New("versi tidak valid: isi materi sudah sama dengan versi tersebut")
+	}
+	if err := refreshMaterialMedia(&previous, material); err != nil {
+		return nil, err
+	}
+
+	if err := saveMaterialRevision(previous, material, userID, fmt.Sprintf("rollback ke versi %d", version)); err != nil {
+		return nil, err
+	}
+
+	return repository.GetMaterialByID(materialID)
+}
+
+// diffLines membuat diff per baris berbasis LCS.
+func diffLines(a, b string) []DiffLine {
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+	result := []DiffLine{}
+
+	if len(oldLines)*len(newLines) > maxLineDiffCells {
+		for _, line := range oldLines {
+			result = append(result, DiffLine{Op: "remove", Text: line})
+		}
+		for _, line := range newLines {
+			result = append(result, DiffLine{Op: "add", Text: line})
+		}
+		return result
+	}
+
+	n, m := len(oldLines), len(newLines)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			result = append(result, DiffLine{Op: "equal", Text: oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: "remove", Text: oldLines[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: "add", Text: newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, DiffLine{Op: "remove", Text: oldLines[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, DiffLine{Op: "add", Text: newLines[j]})
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{"keduanya kosong", "", "", []DiffLine{}},
+		{"dari kosong", "", "a\nb", []DiffLine{{"add", "a"}, {"add", "b"}}},
+		{"menjadi kosong", "a\nb", "", []DiffLine{{"remove", "a"}, {"remove", "b"}}},
+		{"sama", "a\nb", "a\nb", []DiffLine{{"equal", "a"}, {"equal", "b"}}},
+		{"baris diganti", "a\nb\nc", "a\nx\nc", []DiffLine{{"equal", "a"}, {"remove", "b"}, {"add", "x"}, {"equal", "c"}}},
+		{"baris disisipkan", "a\nc", "a\nb\nc", []DiffLine{{"equal", "a"}, {"add", "b"}, {"equal", "c"}}},
+		{"baris dihapus", "a\nb\nc", "a\nc", []DiffLine{{"equal", "a"}, {"remove", "b"}, {"equal", "c"}}},
+		{"CRLF dianggap sama", "a\r\nb", "a\nb", []DiffLine{{"equal", "a"}, {"equal", "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	old := strings.Repeat("a\n", 2001) + "a"
+	updated := strings.Repeat("b\n", 2001) + "b"
+
+	got := diffLines(old, updated)
+	if len(got) != 2*2002 {
+		t.Fatalf("len(diffLines) = %d, want %d", len(got), 2*2002)
+	}
+	if got[0].Op != "remove" || got[len(got)-1].Op != "add" {
+		t.Errorf("diff besar seharusnya berisi semua baris lama dihapus lalu semua baris baru ditambah")
+	}
+}
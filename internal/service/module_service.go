@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"time"
+)
+
+type ModuleDetailsInput struct {
+	Title            string     `json:"title" binding:"required"`
+	AvailableFrom    *time.Time `json:"available_from"`
+	AvailableUntil   *time.Time `json:"available_until"`
+	RequiresPrevious bool       `json:"requires_previous"`
+}
+
+type ReorderInput struct {
+	IDs []uint64 `json:"ids" binding:"required,min=1"` // Semua ID dalam urutan baru
+}
+
+type MoveMaterialInput struct {
+	ModuleID uint64 `json:"module_id" binding:"required"`
+	Position int    `json:"position" binding:"min=0"` // 1-based, 0 = paling akhir
+}
+
+// CreateModule menambahkan modul baru di akhir kelas tanpa mengirim ulang seluruh isi kelas.
+func CreateModule(courseID uint64, input ModuleDetailsInput, userID uint64) (*model.Module, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+	if err := validateAvailabilityWindow(input.AvailableFrom, input.AvailableUntil); err != nil {
+		return nil, err
+	}
+
+	order, err := repository.NextModuleOrder(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &model.Module{
+		CourseID:         courseID,
+		Title:            input.Title,
+		Order:            order,
+		AvailableFrom:    input.AvailableFrom,
+		AvailableUntil:   input.AvailableUntil,
+		RequiresPrevious: input.RequiresPrevious,
+	}
+	if err := repository.CreateModule(module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+// UpdateModule mengganti judul dan jadwal rilis modul. Materi di dalamnya tidak disentuh.
+func UpdateModule(moduleID uint64, input ModuleDetailsInput, userID uint64) (*model.Module, error) {
+	if err := authorize(userID, PermModuleEdit, moduleID); err != nil {
+		return nil, err
+	}
+	if err := validateAvailabilityWindow(input.AvailableFrom, input.AvailableUntil); err != nil {
+		return nil, err
+	}
+
+	module, err := repository.GetModuleByID(moduleID)
+	if err != nil {
+		return nil, errors.New("modul tidak ditemukan")
+	}
+
+	module.Title = input.Title
+	module.AvailableFrom = input.AvailableFrom
+	module.AvailableUntil = input.AvailableUntil
+	module.RequiresPrevious = input.RequiresPrevious
+	if err := repository.UpdateModule(module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func ReorderModules(courseID uint64, input ReorderInput, userID uint64) (*model.Course, error) {
+	if err := authorize(userID, PermCourseEdit, courseID); err != nil {
+		return nil, err
+	}
+	if err := ensureCourseWritable(courseID); err != nil {
+		return nil, err
+	}
+
+	if err := repository.ReorderModules(courseID, input.IDs); err != nil {
+		return nil, err
+	}
+
+	return repository.GetCourseByID(courseID)
+}
+
+func ReorderMaterials(moduleID uint64, input ReorderInput, userID uint64) ([]model.Material, error) {
+	if err := authorize(userID, PermModuleEdit, moduleID); err != nil {
+		return nil, err
+	}
+
+	if err := repository.ReorderMaterials(moduleID, input.IDs); err != nil {
+		return nil, err
+	}
+
+	return repository.GetMaterialsByModuleID(moduleID)
+}
+
+// MoveMaterial memindahkan materi ke modul lain (atau posisi lain di modul yang sama) di kelas yang sama.
+func MoveMaterial(materialID uint64, input MoveMaterialInput, userID uint64) (*model.Material, error) {
+	if err := authorize(userID, PermMaterialEdit, materialID); err != nil {
+		return nil, err
+	}
+	if err := authorize(userID, PermModuleEdit, input.ModuleID); err != nil {
+		return nil, err
+	}
+
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+	source, err := repository.GetModuleByID(material.ModuleID)
+	if err != nil {
+		return nil, errors.New("modul tidak ditemukan")
+	}
+	target, err := repository.GetModuleByID(input.ModuleID)
+	if err != nil {
+		return nil, errors.New("modul tujuan tidak ditemukan")
+	}
+	if source.CourseID != target.CourseID {
+		return nil, errors.New("modul tujuan tidak valid: materi hanya bisa dipindah di dalam kelas yang sama")
+	}
+
+	if err := repository.MoveMaterial(material, target.ID, input.Position); err != nil {
+		return nil, err
+	}
+
+	return repository.GetMaterialByID(materialID)
+}
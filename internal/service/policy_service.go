@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+)
+
+type Permission string
+
+const (
+	PermCourseView           Permission = "course:view"
+	PermCourseTeach          Permission = "course:teach" // Melihat kelas dari sisi pengajar (detail, daftar tugas)
+	PermCourseEdit           Permission = "course:edit"
+	PermCourseDelete         Permission = "course:delete"
+	PermCourseManageStudents Permission = "course:manage_students"
+	PermCourseManageStaff    Permission = "course:manage_staff"
+	PermModuleEdit           Permission = "module:edit"
+	PermMaterialView         Permission = "material:view"
+	PermMaterialTeach        Permission = "material:teach" // Melihat riwayat versi materi
+	PermMaterialEdit         Permission = "material:edit"
+	PermMaterialComplete     Permission = "material:complete"
+	PermAssignmentView       Permission = "assignment:view"
+	PermAssignmentEdit       Permission = "assignment:edit"
+	PermAssignmentSubmit     Permission = "assignment:submit"
+	PermAssignmentGrade      Permission = "assignment:grade" // Melihat seluruh pengumpulan tugas
+	PermSubmissionGrade      Permission = "submission:grade"
+	PermStudentManage        Permission = "student:manage"
+	PermAnnouncementView     Permission = "announcement:view"
+	PermAnnouncementEdit     Permission = "announcement:edit"
+	PermThreadView           Permission = "thread:view"
+	PermThreadReply          Permission = "thread:reply"
+	PermThreadModerate       Permission = "thread:moderate" // Kunci/sembunyikan thread
+	PermReplyModerate        Permission = "reply:moderate"  // Sembunyikan balasan, tandai jawaban
+)
+
+type ResourceType string
+
+const (
+	ResourceCourse       ResourceType = "course"
+	ResourceModule       ResourceType = "module"
+	ResourceMaterial     ResourceType = "material"
+	ResourceAssignment   ResourceType = "assignment"
+	ResourceSubmission   ResourceType = "submission"
+	ResourceStudent      ResourceType = "student"
+	ResourceAnnouncement ResourceType = "announcement"
+	ResourceThread       ResourceType = "thread"
+	ResourceReply        ResourceType = "reply"
+)
+
+// CourseRelation adalah hubungan user terhadap sebuah kelas.
+type CourseRelation string
+
+const (
+	RelationNone      CourseRelation = ""
+	RelationOwner     CourseRelation = "owner"
+	RelationCoTeacher CourseRelation = "co_teacher"
+	RelationTA        CourseRelation = "ta"
+	RelationStudent   CourseRelation = "student"
+)
+
+var (
+	ownerOnly = []CourseRelation{RelationOwner}
+	editors   = []CourseRelation{RelationOwner, RelationCoTeacher}
+	staff     = []CourseRelation{RelationOwner, RelationCoTeacher, RelationTA}
+	members   = []CourseRelation{RelationOwner, RelationCoTeacher, RelationTA, RelationStudent}
+	students  = []CourseRelation{RelationStudent}
+)
+
+type policyRule struct {
+	Resource ResourceType
+	Allow    []CourseRelation
+}
+
+// policies memetakan setiap permission ke jenis resource dan relasi kelas yang diizinkan.
+// Admin selalu diizinkan (override). ResourceStudent tidak memakai relasi kelas,
+// melainkan kepemilikan akun (User.CreatedByID).
+var policies = map[Permission]policyRule{
+	PermCourseView:           {ResourceCourse, members},
+	PermCourseTeach:          {ResourceCourse, staff},
+	PermCourseEdit:           {ResourceCourse, editors},
+	PermCourseDelete:         {ResourceCourse, ownerOnly},
+	PermCourseManageStudents: {ResourceCourse, editors},
+	PermCourseManageStaff:    {ResourceCourse, ownerOnly},
+	PermModuleEdit:           {ResourceModule, editors},
+	PermMaterialView:         {ResourceMaterial, members},
+	PermMaterialTeach:        {ResourceMaterial, staff},
+	PermMaterialEdit:         {ResourceMaterial, editors},
+	PermMaterialComplete:     {ResourceMaterial, students},
+	PermAssignmentView:       {ResourceAssignment, members},
+	PermAssignmentEdit:       {ResourceAssignment, editors},
+	PermAssignmentSubmit:     {ResourceAssignment, students},
+	PermAssignmentGrade:      {ResourceAssignment, staff},
+	PermSubmissionGrade:      {ResourceSubmission, staff},
+	PermStudentManage:        {ResourceStudent, nil},
+	PermAnnouncementView:     {ResourceAnnouncement, members},
+	PermAnnouncementEdit:     {ResourceAnnouncement, editors},
+	PermThreadView:           {ResourceThread, members},
+	PermThreadReply:          {ResourceThread, members},
+	PermThreadModerate:       {ResourceThread, staff},
+	PermReplyModerate:        {ResourceReply, staff},
+}
+
+// mutatingPermissions mengubah isi atau progres kelas, sehingga ditolak pada kelas archived
+// (termasuk untuk admin). Operasi tulis yang memakai PermCourseEdit/PermCourseManageStudents
+// diperiksa di service lewat ensureCourseWritable, karena permission itu juga dipakai untuk membaca.
+var mutatingPermissions = map[Permission]bool{
+	PermModuleEdit:       true,
+	PermMaterialEdit:     true,
+	PermMaterialComplete: true,
+	PermAssignmentEdit:   true,
+	PermAssignmentSubmit: true,
+	PermSubmissionGrade:  true,
+	PermAnnouncementEdit: true,
+	PermThreadReply:      true,
+	PermThreadModerate:   true,
+	PermReplyModerate:    true,
+}
+
+var resourceLabels = map[ResourceType]string{
+	ResourceCourse:       "kelas",
+	ResourceModule:       "modul",
+	ResourceMaterial:     "materi",
+	ResourceAssignment:   "tugas",
+	ResourceSubmission:   "submission",
+	ResourceStudent:      "siswa",
+	ResourceAnnouncement: "pengumuman",
+	ResourceThread:       "diskusi",
+	ResourceReply:        "balasan diskusi",
+}
+
+// Authorize memeriksa apakah user boleh melakukan perm pada resource dengan ID resourceID.
+// role boleh kosong; jika kosong dan dibutuhkan (untuk override admin) akan diambil dari DB.
+// Error yang dikembalikan mengikuti konvensi handler: "... tidak ditemukan" atau "unauthorized: ...".
+func Authorize(userID uint64, role string, perm Permission, resourceID uint64) error {
+	rule, ok := policies[perm]
+	if !ok {
+		return errors.New("unauthorized: permission tidak dikenal")
+	}
+	label := resourceLabels[rule.Resource]
+
+	if rule.Resource == ResourceStudent {
+		student, err := repository.FindUserByID(resourceID)
+		if err != nil {
+			return errors.New("siswa tidak ditemukan")
+		}
+		if student.CreatedByID != nil && *student.CreatedByID == userID {
+			return nil
+		}
+		if isAdmin(userID, role) {
+			return nil
+		}
+		return errors.New("unauthorized: anda tidak memiliki akses ke siswa ini")
+	}
+
+	courseID, err := resolveCourseID(rule.Resource, resourceID)
+	if err != nil {
+		return errors.New(label + " tidak ditemukan")
+	}
+
+	course, err := repository.GetCourseByID(courseID)
+	if err != nil {
+		return errors.New("kelas tidak ditemukan")
+	}
+
+	if mutatingPermissions[perm] && course.Status == model.CourseStatusArchived {
+		return errArchivedCourse
+	}
+
+	relation, err := resolveCourseRelation(userID, course)
+	if err != nil {
+		return err
+	}
+
+	for _, allowed := range rule.Allow {
+		if relation == allowed {
+			if relation == RelationStudent && rule.Resource == ResourceMaterial {
+				return authorizeStudentMaterial(course, resourceID, userID)
+			}
+			return nil
+		}
+	}
+
+	if isAdmin(userID, role) {
+		return nil
+	}
+
+	return errors.New("unauthorized: anda tidak memiliki akses ke " + label + " ini")
+}
+
+// authorizeStudentMaterial menerapkan jadwal rilis dan prasyarat modul untuk mahasiswa.
+func authorizeStudentMaterial(course *model.Course, materialID, studentID uint64) error {
+	reason, err := materialLockReason(course, materialID, studentID)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		return errors.New("unauthorized: materi belum dapat diakses, " + reason)
+	}
+	return nil
+}
+
+// AuthorizePreview memeriksa request dengan token pratinjau. User diperlakukan sebagai mahasiswa
+// kelas previewCourseID: hanya permission milik mahasiswa dan hanya resource di kelas tersebut.
+// Pengajar juga harus masih mengajar kelas itu.
+func AuthorizePreview(userID uint64, previewCourseID uint64, perm Permission, resourceID uint64) error {
+	rule, ok := policies[perm]
+	if !ok || rule.Resource == ResourceStudent {
+		return errors.New("unauthorized: permission tidak tersedia dalam mode pratinjau")
+	}
+	label := resourceLabels[rule.Resource]
+
+	allowed := false
+	for _, relation := range rule.Allow {
+		if relation == RelationStudent {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New("unauthorized: permission tidak tersedia dalam mode pratinjau")
+	}
+
+	courseID, err := resolveCourseID(rule.Resource, resourceID)
+	if err != nil {
+		return errors.New(label + " tidak ditemukan")
+	}
+	if courseID != previewCourseID {
+		return errors.New("unauthorized: " + label + " ini berada di luar kelas yang dipratinjau")
+	}
+
+	return Authorize(userID, "", PermCourseTeach, previewCourseID)
+}
+
+// authorize adalah varian Authorize untuk dipakai di dalam service (role diambil dari DB bila perlu).
+func authorize(userID uint64, perm Permission, resourceID uint64) error {
+	return Authorize(userID, "", perm, resourceID)
+}
+
+func isAdmin(userID uint64, role string) bool {
+	if role == "" {
+		user, err := repository.FindUserByID(userID)
+		if err != nil {
+			return false
+		}
+		role = string(user.Role)
+	}
+	return role == string(model.RoleAdmin)
+}
+
+// resolveCourseID menelusuri resource sampai ke kelas pemiliknya.
+func resolveCourseID(resource ResourceType, id uint64) (uint64, error) {
+	switch resource {
+	case ResourceCourse:
+		return id, nil
+	case ResourceModule:
+		module, err := repository.GetModuleByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return module.CourseID, nil
+	case ResourceMaterial:
+		material, err := repository.GetMaterialByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return resolveCourseID(ResourceModule, material.ModuleID)
+	case ResourceAssignment:
+		assignment, err := repository.GetAssignmentByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return assignment.CourseID, nil
+	case ResourceSubmission:
+		submission, err := repository.GetSubmissionByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return resolveCourseID(ResourceAssignment, submission.AssignmentID)
+	case ResourceAnnouncement:
+		announcement, err := repository.FindAnnouncementByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return announcement.CourseID, nil
+	case ResourceThread:
+		thread, err := repository.FindDiscussionThreadByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return thread.CourseID, nil
+	case ResourceReply:
+		reply, err := repository.FindDiscussionReplyByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return resolveCourseID(ResourceThread, reply.ThreadID)
+	}
+	return 0, errors.New("resource tidak dikenal")
+}
+
+func resolveCourseRelation(userID uint64, course *model.Course) (CourseRelation, error) {
+	if course.TeacherID == userID {
+		return RelationOwner, nil
+	}
+
+	if member, err := repository.FindCourseStaff(course.ID, userID); err == nil {
+		switch member.Role {
+		case model.StaffRoleCoTeacher:
+			return RelationCoTeacher, nil
+		case model.StaffRoleTA:
+			return RelationTA, nil
+		}
+	}
+
+	isStudent, err := repository.IsStudentInCourse(course.ID, userID)
+	if err != nil {
+		return RelationNone, err
+	}
+	if isStudent {
+		return RelationStudent, nil
+	}
+
+	return RelationNone, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+)
+
+// previewStudentID adalah ID mahasiswa sintetis dalam mode pratinjau. Tidak ada user dengan
+// ID 0, sehingga tidak ada enrollment, penyelesaian materi, maupun pengumpulan tugas.
+const previewStudentID uint64 = 0
+
+type StudentPreviewInput struct {
+	Categories []string `json:"categories"`
+}
+
+// PreviewStudent adalah data "user" yang dikembalikan /auth/me selama mode pratinjau.
+type PreviewStudent struct {
+	ID            uint64                      `json:"id"`
+	Name          string                      `json:"name"`
+	Role          model.UserRole              `json:"role"`
+	Accessibility *model.AccessibilityProfile `json:"accessibility"`
+	IsPreview     bool                        `json:"is_preview"`
+}
+
+type StudentPreviewSession struct {
+	Token     string          `json:"token"`
+	ExpiresIn int64           `json:"expires_in"`
+	CourseID  uint64          `json:"course_id"`
+	Student   *PreviewStudent `json:"student"`
+}
+
+// StartStudentPreview menerbitkan token pratinjau agar pengajar bisa membuka endpoint mahasiswa
+// untuk kelas courseID sebagai mahasiswa dengan profil aksesibilitas pilihan.
+func StartStudentPreview(courseID uint64, userID uint64, sessionID uint64, input StudentPreviewInput) (*StudentPreviewSession, error) {
+	if err := authorize(userID, PermCourseTeach, courseID); err != nil {
+		return nil, err
+	}
+
+	categories := make([]string, 0, len(input.Categories))
+	for _, category := range input.Categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category != "" {
+			categories = append(categories, category)
+		}
+	}
+
+	token, err := utils.GeneratePreviewToken(userID, sessionID, courseID, categories)
+	if err != nil {
+		return nil, errors.New("gagal membuat token pratinjau")
+	}
+
+	return &StudentPreviewSession{
+		Token:     token,
+		ExpiresIn: int64(utils.PreviewTokenTTL.Seconds()),
+		CourseID:  courseID,
+		Student:   GetPreviewStudent(categories),
+	}, nil
+}
+
+func GetPreviewStudent(categories []string) *PreviewStudent {
+	return &PreviewStudent{
+		ID:            previewStudentID,
+		Name:          "Pratinjau Mahasiswa",
+		Role:          model.RoleStudent,
+		Accessibility: buildAccessibilityProfile(previewStudentID, categories),
+		IsPreview:     true,
+	}
+}
+
+func PreviewCourseDetail(courseID uint64, categories []string) (*model.Course, error) {
+	return studentCourseView(courseID, previewStudentID, buildAccessibilityProfile(previewStudentID, categories))
+}
+
+func PreviewCourseAnnouncements(courseID uint64, categories []string) ([]model.Announcement, error) {
+	return deliverCourseAnnouncements(courseID, previewStudentID, buildAccessibilityProfile(previewStudentID, categories))
+}
+
+func PreviewCourseAssignments(courseID uint64) ([]model.Assignment, error) {
+	return repository.GetAssignmentsByCourseID(courseID)
+}
+
+func PreviewMaterialDetail(materialID uint64) (*model.Material, error) {
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+
+	module, err := repository.GetModuleByID(material.ModuleID)
+	if err != nil {
+		return nil, errors.New("modul tidak ditemukan")
+	}
+	if err := ensureMaterialAvailable(materialID, module.CourseID, previewStudentID); err != nil {
+		return nil, err
+	}
+	return material, nil
+}
+
+func PreviewAssignmentDetail(assignmentID uint64) (*model.Assignment, error) {
+	assignment, err := repository.GetAssignmentByID(assignmentID)
+	if err != nil {
+		return nil, errors.New("tugas tidak ditemukan")
+	}
+
+	studentAssignmentView(assignment, previewStudentID)
+	return assignment, nil
+}
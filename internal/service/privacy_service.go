@@ -0,0 +1,247 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"time"
+)
+
+const (
+	accountDeletionTTL       = 24 * time.Hour
+	anonymizedUserName       = "Pengguna Terhapus"
+	anonymizedEmailDomain    = "deleted.invalid"
+	accountDeletionTokenSize = 32
+)
+
+// UserDataExport berisi seluruh data pribadi user yang disimpan platform.
+type UserDataExport struct {
+	ExportedAt    time.Time                       `json:"exported_at"`
+	Profile       *model.User                     `json:"profile"`
+	Accessibility *model.AccessibilityProfile     `json:"accessibility_profile"`
+	Courses       []model.Course                  `json:"courses"`
+	Submissions   []repository.ExportedSubmission `json:"submissions"`
+	Completions   []repository.ExportedCompletion `json:"material_completions"`
+	Progress      []model.MaterialProgress        `json:"material_progress"`
+	Activities    []model.Activity                `json:"activities"`
+	LoginHistory  []model.LoginAttempt            `json:"login_history"`
+	Annotations   []model.MaterialAnnotation      `json:"annotations"`
+	Bookmarks     []model.MaterialBookmark        `json:"bookmarks"`
+}
+
+type AccountDeletionInput struct {
+	Reason string `json:"reason"`
+}
+
+type ConfirmAccountDeletionInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func ExportUserData(userID uint64) (*UserDataExport, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	// Profil aksesibilitas ditampilkan terpisah, bukan di dalam profil
+	profile := *user
+	profile.Accessibility = nil
+
+	export := &UserDataExport{
+		ExportedAt:    time.Now(),
+		Profile:       &profile,
+		Accessibility: user.Accessibility,
+	}
+
+	if export.Courses, err = repository.GetCoursesByStudentID(userID, "all"); err != nil {
+		return nil, err
+	}
+	if export.Submissions, err = repository.GetSubmissionsForExport(userID); err != nil {
+		return nil, err
+	}
+	if export.Completions, err = repository.GetCompletionsForExport(userID); err != nil {
+		return nil, err
+	}
+	if export.Progress, err = repository.GetUserMaterialProgress(userID); err != nil {
+		return nil, err
+	}
+	if export.Activities, err = repository.GetActivitiesByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.LoginHistory, err = repository.GetLoginAttemptsByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.Annotations, err = repository.GetUserAnnotations(userID); err != nil {
+		return nil, err
+	}
+	if export.Bookmarks, err = repository.GetUserBookmarks(userID, 0); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ExportUserDataZip mengemas ExportUserData menjadi arsip ZIP dengan satu file JSON per kategori.
+func ExportUserDataZip(userID uint64) ([]byte, error) {
+	export, err := ExportUserData(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		Name string
+		Data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"accessibility_profile.json", export.Accessibility},
+		{"courses.json", export.Courses},
+		{"submissions.json", export.Submissions},
+		{"material_completions.json", export.Completions},
+		{"material_progress.json", export.Progress},
+		{"activities.json", export.Activities},
+		{"login_history.json", export.LoginHistory},
+		{"annotations.json", export.Annotations},
+		{"bookmarks.json", export.Bookmarks},
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.Name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.MarshalIndent(f.Data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RequestAccountDeletion mengirim tautan konfirmasi ke email user. Akun baru dianonimkan
+// setelah tautan tersebut dibuka, sehingga token yang dicuri saja tidak cukup.
+func RequestAccountDeletion(userID uint64, input AccountDeletionInput) error {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := ensureAccountDeletable(user); err != nil {
+		return err
+	}
+
+	if err := repository.DeletePendingAccountDeletionRequests(userID); err != nil {
+		return err
+	}
+
+	token := utils.GenerateRandomToken(accountDeletionTokenSize)
+	if err := repository.CreateAccountDeletionRequest(&model.AccountDeletionRequest{
+		UserID:    userID,
+		TokenHash: utils.HashToken(token),
+		Reason:    input.Reason,
+		ExpiresAt: time.Now().Add(accountDeletionTTL),
+	}); err != nil {
+		return err
+	}
+
+	return utils.SendAccountDeletionEmail(user.Email, token)
+}
+
+func CancelAccountDeletion(userID uint64) error {
+	if _, err := repository.FindPendingAccountDeletionRequest(userID); err != nil {
+		return errors.New("permintaan hapus akun tidak ditemukan")
+	}
+	return repository.DeletePendingAccountDeletionRequests(userID)
+}
+
+func ConfirmAccountDeletion(input ConfirmAccountDeletionInput) error {
+	request, err := repository.FindAccountDeletionRequestByHash(utils.HashToken(input.Token))
+	if err != nil || request.ConfirmedAt != nil {
+		return errors.New("token hapus akun tidak valid")
+	}
+
+	if time.Now().After(request.ExpiresAt) {
+		return errors.New("token hapus akun sudah kadaluarsa. silahkan ajukan ulang")
+	}
+
+	user, err := repository.FindUserByID(request.UserID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := ensureAccountDeletable(user); err != nil {
+		return err
+	}
+
+	if err := anonymizeUser(user.ID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	request.ConfirmedAt = &now
+	return repository.UpdateAccountDeletionRequest(request)
+}
+
+func ensureAccountDeletable(user *model.User) error {
+	if user.AnonymizedAt != nil {
+		return errors.New("akun sudah dihapus")
+	}
+
+	owned, err := repository.CountCoursesOwnedBy(user.ID)
+	if err != nil {
+		return err
+	}
+	if owned > 0 {
+		return fmt.Errorf("akun masih memiliki %d kelas. hapus kelas tersebut terlebih dahulu", owned)
+	}
+	return nil
+}
+
+// anonymizeUser mencabut semua sesi, menghapus data pribadi user (lihat repository.AnonymizeUser)
+// lalu menghapus file upload lokalnya.
+func anonymizeUser(userID uint64) error {
+	if err := RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	unusablePassword, err := utils.HashPassword(utils.GenerateRandomToken(32))
+	if err != nil {
+		return err
+	}
+
+	placeholderEmail := fmt.Sprintf("deleted-%d@%s", userID, anonymizedEmailDomain)
+	uploadedFiles, err := repository.AnonymizeUser(userID, anonymizedUserName, placeholderEmail, unusablePassword)
+	if err != nil {
+		return err
+	}
+
+	// File di storage remote (Supabase) tidak dihapus di sini; URL-nya sudah tidak tersimpan
+	for _, fileURL := range uploadedFiles {
+		if localPath, ok := utils.LocalStoragePath(fileURL); ok {
+			if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to remove uploaded file %s of anonymized user #%d: %v\n", localPath, userID, err)
+			}
+		}
+	}
+	return nil
+}
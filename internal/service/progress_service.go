@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Waktu belajar per heartbeat dibatasi agar tab yang ditinggal terbuka atau klien yang
+	// mengirim elapsed besar tidak menggelembungkan waktu belajar.
+	heartbeatMaxElapsedSec = 120
+	heartbeatSlackSec      = 5
+
+	// Ambang penyelesaian otomatis per jenis materi
+	mediaWatchRatio    = 0.9 // posisi terjauh video/audio dari durasi
+	mediaTimeRatio     = 0.5 // waktu belajar minimal dari durasi (mencegah lompat ke akhir)
+	textScrollPercent  = 90
+	textWordsPerMinute = 200
+	textTimeRatio      = 0.5 // dari perkiraan waktu baca
+	minSecondsPerPage  = 10
+	maxPageTimeSec     = 600
+	minViewSec         = 10 // gambar, atau batas bawah waktu baca teks
+)
+
+type ProgressHeartbeatInput struct {
+	ElapsedSec    int      `json:"elapsed_sec" binding:"min=0,max=300"` // Detik sejak heartbeat sebelumnya
+	PositionSec   *int     `json:"position_sec" binding:"omitempty,min=0"`
+	Page          *int     `json:"page" binding:"omitempty,min=1"`
+	ScrollPercent *float64 `json:"scroll_percent" binding:"omitempty,min=0,max=100"`
+}
+
+type MaterialProgressResult struct {
+	Progress      *model.MaterialProgress `json:"progress"`
+	IsCompleted   bool                    `json:"is_completed"`
+	AutoCompleted bool                    `json:"auto_completed"` // Baru saja ditandai selesai oleh heartbeat ini
+}
+
+// RecordMaterialProgress menyimpan heartbeat dari pemutar/penampil materi. Begitu ambang jenis
+// materi tercapai, materi ditandai selesai sekali; jika mahasiswa kemudian membatalkan tanda selesai
+// secara manual, heartbeat berikutnya tidak menandainya lagi.
+func RecordMaterialProgress(materialID uint64, input ProgressHeartbeatInput, userID uint64) (*MaterialProgressResult, error) {
+	if err := authorize(userID, PermMaterialComplete, materialID); err != nil {
+		return nil, err
+	}
+	material, err := repository.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan")
+	}
+
+	progress, err := repository.FindMaterialProgress(userID, materialID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		progress = &model.MaterialProgress{UserID: userID, MaterialID: materialID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	elapsed := input.ElapsedSec
+	if elapsed > heartbeatMaxElapsedSec {
+		elapsed = heartbeatMaxElapsedSec
+	}
+	if progress.LastHeartbeatAt != nil {
+		if gap := int(now.Sub(*progress.LastHeartbeatAt).Seconds()) + heartbeatSlackSec; elapsed > gap {
+			elapsed = gap
+		}
+	}
+	progress.TimeSpentSec += elapsed
+	progress.LastHeartbeatAt = &now
+
+	if input.PositionSec != nil {
+		position := *input.PositionSec
+		if duration := materialDurationSec(material); duration > 0 && position > duration {
+			position = duration
+		}
+		progress.LastPositionSec = position
+		if position > progress.MaxPositionSec {
+			progress.MaxPositionSec = position
+		}
+	}
+	if input.Page != nil {
+		page := *input.Page
+		if pages := material.Media.PageCount; pages > 0 && page > pages {
+			page = pages
+		}
+		progress.LastPage = page
+		if page > progress.MaxPage {
+			progress.MaxPage = page
+		}
+	}
+	if input.ScrollPercent != nil {
+		progress.LastScrollPct = *input.ScrollPercent
+		if progress.LastScrollPct > progress.MaxScrollPct {
+			progress.MaxScrollPct = progress.LastScrollPct
+		}
+	}
+
+	progress.ProgressPercent = engagementPercent(material, progress)
+
+	result := &MaterialProgressResult{Progress: progress}
+	if progress.ProgressPercent >= 100 && progress.AutoCompletedAt == nil {
+		progress.AutoCompletedAt = &now
+		changed, err := repository.MarkMaterialCompleted(userID, materialID)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.AutoCompleted = true
+			recordMaterialCompletedActivity(userID, materialID)
+		}
+	}
+
+	if err := repository.SaveMaterialProgress(progress); err != nil {
+		return nil, err
+	}
+	result.IsCompleted = repository.GetMaterialCompletionStatus(userID, materialID)
+	return result, nil
+}
+
+// materialDurationSec memakai durasi hasil pembacaan file, atau durasi yang diisi pengajar.
+func materialDurationSec(material *model.Material) int {
+	if material.Media.DurationSec > 0 {
+		return material.Media.DurationSec
+	}
+	return material.DurationMin * 60
+}
+
+// engagementPercent menghitung progres (0-100) menuju ambang selesai: nilai terkecil antara
+// cakupan isi (posisi/halaman/scroll terjauh) dan waktu belajar. Materi yang panjangnya tidak
+// diketahui tidak diselesaikan otomatis; tanda selesai manual tetap tersedia.
+func engagementPercent(material *model.Material, progress *model.MaterialProgress) float64 {
+	var coverage, timeRatio float64
+	spent := float64(progress.TimeSpentSec)
+
+	switch material.Type {
+	case model.TypeYoutube, model.TypeAudio:
+		duration := float64(materialDurationSec(material))
+		if duration == 0 {
+			return 0
+		}
+		coverage = float64(progress.MaxPositionSec) / (duration * mediaWatchRatio)
+		timeRatio = spent / (duration * mediaTimeRatio)
+	case model.TypePDF, model.TypeSlides:
+		pages := material.Media.PageCount
+		if pages == 0 {
+			return 0
+		}
+		coverage = float64(progress.MaxPage) / float64(pages)
+		timeRatio = spent / math.Min(float64(pages*minSecondsPerPage), maxPageTimeSec)
+	case model.TypeText:
+		readSec := float64(len(strings.Fields(material.RawContent))) * 60 / textWordsPerMinute * textTimeRatio
+		coverage = progress.MaxScrollPct / textScrollPercent
+		timeRatio = spent / math.Max(readSec, minViewSec)
+	default:
+		coverage = 1
+		timeRatio = spent / minViewSec
+	}
+
+	percent := math.Min(math.Min(coverage, timeRatio), 1) * 100
+	return math.Round(percent*10) / 10
+}
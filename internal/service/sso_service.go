@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/oidc"
+	"ramah-disabilitas-be/pkg/utils"
+	"time"
+)
+
+const ssoStateTTL = 10 * time.Minute
+
+type SSOProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type SSOCallbackInput struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+func ListSSOProviders() []SSOProviderInfo {
+	providers := []SSOProviderInfo{}
+	for _, p := range oidc.Providers() {
+		providers = append(providers, SSOProviderInfo{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return providers
+}
+
+// StartSSOLogin menyiapkan state, nonce dan PKCE verifier lalu mengembalikan URL
+// halaman login penyedia SSO yang harus dibuka oleh frontend.
+func StartSSOLogin(providerName string) (string, error) {
+	provider, ok := oidc.GetProvider(providerName)
+	if !ok {
+		return "", errors.New("penyedia SSO tidak ditemukan")
+	}
+
+	state := oidc.NewState()
+	nonce := oidc.NewState()
+	verifier := oidc.NewPKCEVerifier()
+
+	authURL, err := provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		log.Printf("SSO %s: %v\n", provider.Name, err)
+		return "", errors.New("penyedia SSO sedang tidak dapat dihubungi")
+	}
+
+	repository.DeleteExpiredSSOLoginStates()
+	if err := repository.CreateSSOLoginState(&model.SSOLoginState{
+		Provider:     provider.Name,
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(ssoStateTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteSSOLogin menukar authorization code dari penyedia SSO dengan sesi aplikasi.
+// User ditautkan lewat identitas yang sudah ada, email yang terverifikasi, atau
+// dibuat otomatis bila penyedia mengizinkan auto-provisioning.
+func CompleteSSOLogin(providerName string, input SSOCallbackInput, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	provider, ok := oidc.GetProvider(providerName)
+	if !ok {
+		return nil, nil, errors.New("penyedia SSO tidak ditemukan")
+	}
+
+	state, err := repository.ConsumeSSOLoginState(utils.HashToken(input.State))
+	if err != nil || state.Provider != provider.Name || time.Now().After(state.ExpiresAt) {
+		return nil, nil, errors.New("sesi login SSO tidak valid atau kadaluarsa. silahkan ulangi")
+	}
+
+	identity, err := provider.Exchange(input.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("SSO %s: %v\n", provider.Name, err)
+		return nil, nil, errors.New("login SSO gagal diverifikasi")
+	}
+
+	user, err := resolveSSOUser(provider, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return continueLogin(user, meta)
+}
+
+func resolveSSOUser(provider *oidc.Provider, identity *oidc.Identity) (*model.User, error) {
+	if linked, err := repository.FindUserIdentity(provider.Name, identity.Subject); err == nil {
+		repository.TouchUserIdentity(linked.ID)
+		return repository.FindUserByID(linked.UserID)
+	}
+
+	if identity.Email == "" {
+		return nil, errors.New("penyedia SSO tidak mengirimkan email")
+	}
+
+	if !provider.EmailAllowed(identity.Email) {
+		return nil, errors.New("unauthorized: domain email tidak diizinkan untuk SSO ini")
+	}
+
+	// Menautkan ke akun lokal hanya aman jika penyedia menjamin kepemilikan email
+	if !identity.EmailVerified {
+		return nil, errors.New("unauthorized: email belum diverifikasi oleh penyedia SSO")
+	}
+
+	user, err := repository.FindUserByEmailIgnoreCase(identity.Email)
+	if err != nil {
+		if !provider.AutoProvision {
+			return nil, errors.New("unauthorized: akun belum terdaftar. hubungi admin")
+		}
+		if user, err = provisionSSOUser(provider, identity); err != nil {
+			return nil, err
+		}
+	} else if !user.IsVerified {
+		repository.UpdateUserFields(user.ID, map[string]interface{}{
+			"is_verified":             true,
+			"verification_token":      "",
+			"verification_expires_at": nil,
+		})
+		user.IsVerified = true
+	}
+
+	now := time.Now()
+	if err := repository.CreateUserIdentity(&model.UserIdentity{
+		UserID:      user.ID,
+		Provider:    provider.Name,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func provisionSSOUser(provider *oidc.Provider, identity *oidc.Identity) (*model.User, error) {
+	var role model.UserRole
+	switch provider.MapRole(identity.Claims) {
+	case "dosen", "lecturer":
+		role = model.RoleLecturer
+	case "mahasiswa", "student":
+		role = model.RoleStudent
+	default:
+		// Akun admin tidak pernah dibuat dari klaim SSO, hanya lewat admin API
+		return nil, errors.New("unauthorized: role dari penyedia SSO tidak diizinkan")
+	}
+
+	// Password acak yang tidak diketahui siapa pun; user tetap bisa memakai lupa password
+	hashedPassword, err := utils.HashPassword(utils.GenerateRandomToken(32))
+	if err != nil {
+		return nil, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+
+	user := &model.User{
+		Name:       name,
+		Email:      identity.Email,
+		Password:   hashedPassword,
+		Role:       role,
+		IsVerified: true,
+	}
+	if err := repository.CreateUser(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"ramah-disabilitas-be/internal/model"
+	"ramah-disabilitas-be/internal/repository"
+	"ramah-disabilitas-be/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	twoFactorIssuer    = "Ramah Disabilitas"
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 5 // byte acak per kode, ditampilkan sebagai xxxxx-xxxxx
+)
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth://, dirender menjadi QR code oleh frontend
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorInput struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // Kode authenticator atau kode cadangan
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+// TwoFactorRequiredError dikembalikan Login ketika password benar tetapi user
+// masih harus menyelesaikan langkah kedua di /auth/login/2fa.
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+	ExpiresIn      int64
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "verifikasi dua langkah diperlukan"
+}
+
+// twoFactorRequiredForRole membaca kebijakan REQUIRE_2FA_ROLES (mis. "lecturer,admin").
+// Role yang terdaftar wajib mengaktifkan 2FA sebelum bisa memakai fitur lain.
+func twoFactorRequiredForRole(role model.UserRole) bool {
+	for _, r := range strings.Split(os.Getenv("REQUIRE_2FA_ROLES"), ",") {
+		if strings.TrimSpace(r) == string(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetTwoFactorStatus(userID uint64) (*TwoFactorStatus, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	remaining, err := repository.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorStatus{
+		Enabled:                user.TwoFactorEnabled,
+		Required:               twoFactorRequiredForRole(user.Role),
+		RemainingRecoveryCodes: remaining,
+	}, nil
+}
+
+// SetupTwoFactor membuat secret baru yang belum aktif sampai dikonfirmasi lewat EnableTwoFactor.
+func SetupTwoFactor(userID uint64) (*TwoFactorSetupResponse, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, errors.New("2FA sudah aktif. nonaktifkan terlebih dahulu untuk mengganti perangkat")
+	}
+
+	secret := utils.GenerateTOTPSecret()
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{
+		"two_factor_secret":    secret,
+		"two_factor_last_step": 0,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, user.Email, twoFactorIssuer),
+	}, nil
+}
+
+// EnableTwoFactor mengaktifkan 2FA setelah kode pertama dari authenticator cocok.
+// Kode cadangan hanya ditampilkan sekali di sini. Token untuk sesi saat ini diterbitkan
+// ulang agar pembatasan "wajib aktivasi 2FA" langsung hilang.
+func EnableTwoFactor(userID uint64, sessionID uint64, input TwoFactorCodeInput) ([]string, *AuthTokens, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, nil, errors.New("user tidak ditemukan")
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, nil, errors.New("2FA sudah aktif")
+	}
+
+	if user.TwoFactorSecret == "" {
+		return nil, nil, errors.New("2FA belum disiapkan. panggil endpoint setup terlebih dahulu")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TwoFactorSecret, input.Code, time.Now())
+	if !ok {
+		return nil, nil, errors.New("kode 2FA tidak valid")
+	}
+
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{
+		"two_factor_enabled":   true,
+		"two_factor_last_step": step,
+	}); err != nil {
+		return nil, nil, err
+	}
+	user.TwoFactorEnabled = true
+
+	codes, err := generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := issueTokens(user, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return codes, tokens, nil
+}
+
+func DisableTwoFactor(userID uint64, input DisableTwoFactorInput) error {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if !user.TwoFactorEnabled {
+		return errors.New("2FA belum aktif")
+	}
+
+	if twoFactorRequiredForRole(user.Role) {
+		return errors.New("unauthorized: 2FA wajib untuk role anda dan tidak dapat dinonaktifkan")
+	}
+
+	if !utils.CheckPasswordHash(input.Password, user.Password) {
+		return errors.New("password tidak sesuai")
+	}
+
+	if !verifyTwoFactorCode(user, input.Code) {
+		return errors.New("kode 2FA tidak valid")
+	}
+
+	return clearTwoFactor(userID)
+}
+
+func RegenerateRecoveryCodes(userID uint64, input TwoFactorCodeInput) ([]string, error) {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("2FA belum aktif")
+	}
+
+	if !verifyTwoFactorCode(user, input.Code) {
+		return nil, errors.New("kode 2FA tidak valid")
+	}
+
+	return generateRecoveryCodes(userID)
+}
+
+// ResetTwoFactorByAdmin mematikan 2FA user yang kehilangan perangkat dan kode cadangannya.
+// Jika role user mewajibkan 2FA, user akan diminta mendaftar ulang saat login berikutnya.
+func ResetTwoFactorByAdmin(userID uint64, adminID uint64, meta SessionMeta) error {
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if err := clearTwoFactor(userID); err != nil {
+		return err
+	}
+
+	if err := RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	recordAudit(adminID, meta, auditEntry{
+		Action:     model.AuditUserTwoFactorReset,
+		TargetType: "user",
+		TargetID:   userID,
+		Before:     map[string]interface{}{"two_factor_enabled": user.TwoFactorEnabled},
+		After:      map[string]interface{}{"two_factor_enabled": false},
+	})
+
+	return nil
+}
+
+// VerifyTwoFactorLogin adalah langkah kedua login: menukar challenge token + kode 2FA
+// dengan sesi baru. Kode yang salah dihitung ke lockout akun yang sama dengan password.
+func VerifyTwoFactorLogin(input TwoFactorLoginInput, meta SessionMeta) (*model.User, *AuthTokens, error) {
+	userID, err := utils.ParseTwoFactorChallenge(input.ChallengeToken)
+	if err != nil {
+		return nil, nil, errors.New("sesi login tidak valid atau kadaluarsa. silahkan login ulang")
+	}
+
+	user, err := repository.FindUserByID(userID)
+	if err != nil {
+		return nil, nil, errors.New("sesi login tidak valid atau kadaluarsa. silahkan login ulang")
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		recordLoginAttempt(user.Email, &user.ID, meta, false)
+		return nil, nil, lockedError(*user.LockedUntil)
+	}
+
+	if user.DeactivatedAt != nil {
+		return nil, nil, errors.New("akun anda telah dinonaktifkan. silahkan hubungi admin")
+	}
+
+	if !user.TwoFactorEnabled {
+		return nil, nil, errors.New("sesi login tidak valid atau kadaluarsa. silahkan login ulang")
+	}
+
+	if !verifyTwoFactorCode(user, input.Code) {
+		recordLoginAttempt(user.Email, &user.ID, meta, false)
+		if lockedUntil := registerFailedLogin(user); lockedUntil != nil {
+			return nil, nil, lockedError(*lockedUntil)
+		}
+		return nil, nil, errors.New("kode 2FA tidak valid")
+	}
+
+	return completeLogin(user, meta)
+}
+
+// verifyTwoFactorCode menerima kode TOTP (sekali pakai per time-step) atau kode cadangan.
+func verifyTwoFactorCode(user *model.User, code string) bool {
+	code = strings.TrimSpace(code)
+
+	if step, ok := utils.ValidateTOTP(user.TwoFactorSecret, code, time.Now()); ok {
+		claimed, err := repository.ClaimTOTPStep(user.ID, step)
+		if err != nil || !claimed {
+			return false
+		}
+		user.TwoFactorLastStep = step
+		return true
+	}
+
+	used, err := repository.UseRecoveryCode(user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+	return err == nil && used
+}
+
+func generateRecoveryCodes(userID uint64) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]model.TwoFactorRecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		raw := utils.GenerateRandomToken(recoveryCodeLength)
+		codes[i] = raw[:len(raw)/2] + "-" + raw[len(raw)/2:]
+		records[i] = model.TwoFactorRecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(raw),
+		}
+	}
+
+	if err := repository.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+func clearTwoFactor(userID uint64) error {
+	if err := repository.UpdateUserFields(userID, map[string]interface{}{
+		"two_factor_enabled":   false,
+		"two_factor_secret":    "",
+		"two_factor_last_step": 0,
+	}); err != nil {
+		return err
+	}
+	return repository.DeleteRecoveryCodesByUserID(userID)
+}
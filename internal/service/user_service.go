@@ -34,6 +34,8 @@ func CreateStudent(input CreateStudentInput, creatorID *uint64) (*model.User, er
 		Role:        model.RoleStudent,
 		IsVerified:  true,
 		CreatedByID: creatorID,
+		// Password ditentukan dosen (sering kali bawaan "student123"), jadi mahasiswa wajib menggantinya
+		MustChangePassword: true,
 	}
 
 	if err := repository.CreateUser(user); err != nil {
@@ -56,11 +58,22 @@ func CreateStudent(input CreateStudentInput, creatorID *uint64) (*model.User, er
 }
 
 func UpdateAccessibilityProfile(userID uint64, input AccessibilityInput) (*model.AccessibilityProfile, error) {
+	profile := buildAccessibilityProfile(userID, input.Categories)
+
+	if err := repository.SaveAccessibilityProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// buildAccessibilityProfile menerjemahkan kategori disabilitas menjadi kebutuhan aksesibilitas.
+func buildAccessibilityProfile(userID uint64, categories []string) *model.AccessibilityProfile {
 	profile := &model.AccessibilityProfile{
 		UserID: userID,
 	}
 
-	for _, category := range input.Categories {
+	for _, category := range categories {
 		category = strings.ToLower(strings.TrimSpace(category))
 		switch category {
 		case "a", "vision", "penglihatan", "tuna netra", "tuna_netra":
@@ -85,11 +98,7 @@ func UpdateAccessibilityProfile(userID uint64, input AccessibilityInput) (*model
 		}
 	}
 
-	if err := repository.SaveAccessibilityProfile(profile); err != nil {
-		return nil, err
-	}
-
-	return profile, nil
+	return profile
 }
 
 func GetAccessibilityProfile(userID uint64) (*model.AccessibilityProfile, error) {
@@ -156,16 +165,26 @@ func ImportStudentsFromCSV(reader io.Reader) ([]*model.User, error) {
 	return createdUsers, nil
 }
 
-func UpdateStudentByLecturer(studentID uint64, input CreateStudentInput, teacherID uint64) (*model.User, error) {
-	// 1. Get User
+func UpdateStudentByLecturer(studentID uint64, input CreateStudentInput, teacherID uint64, meta SessionMeta) (*model.User, error) {
+	// 1. Check Ownership (CreatedBy)
+	if err := authorize(teacherID, PermStudentManage, studentID); err != nil {
+		return nil, err
+	}
+
+	// 2. Get User
 	user, err := repository.FindUserByID(studentID)
 	if err != nil {
 		return nil, errors.New("siswa tidak ditemukan")
 	}
 
-	// 2. Check Ownership (CreatedBy)
-	if user.CreatedByID == nil || *user.CreatedByID != teacherID {
-		return nil, errors.New("unauthorized: anda tidak berhak mengedit siswa ini")
+	// audit_logs tidak bisa dihapus, jadi yang dicatat hanya kolom yang berubah, bukan nilainya
+	// (nama dan email tidak boleh tertinggal setelah akun dianonimkan)
+	changedFields := []string{}
+	if user.Name != input.Name {
+		changedFields = append(changedFields, "name")
+	}
+	if user.Email != input.Email {
+		changedFields = append(changedFields, "email")
 	}
 
 	// 3. Update basic info
@@ -173,10 +192,14 @@ func UpdateStudentByLecturer(studentID uint64, input CreateStudentInput, teacher
 	user.Email = input.Email
 
 	// Update password if provided
+	passwordChanged := false
 	if input.Password != "" {
 		hashed, err := utils.HashPassword(input.Password)
 		if err == nil {
 			user.Password = hashed
+			user.MustChangePassword = true
+			passwordChanged = true
+			changedFields = append(changedFields, "password")
 		}
 	}
 
@@ -184,21 +207,42 @@ func UpdateStudentByLecturer(studentID uint64, input CreateStudentInput, teacher
 		return nil, err
 	}
 
+	// Password berubah: paksa login ulang di semua perangkat
+	if passwordChanged {
+		RevokeUserSessions(user.ID)
+	}
+
+	recordAudit(teacherID, meta, auditEntry{
+		Action:     model.AuditStudentUpdate,
+		TargetType: "user",
+		TargetID:   user.ID,
+		After:      map[string]interface{}{"changed_fields": changedFields},
+	})
+
 	return user, nil
 }
 
-func DeleteStudentByLecturer(studentID uint64, teacherID uint64) error {
-	// 1. Get User
-	user, err := repository.FindUserByID(studentID)
-	if err != nil {
+func DeleteStudentByLecturer(studentID uint64, teacherID uint64, meta SessionMeta) error {
+	// 1. Check Ownership
+	if err := authorize(teacherID, PermStudentManage, studentID); err != nil {
+		return err
+	}
+
+	if _, err := repository.FindUserByID(studentID); err != nil {
 		return errors.New("siswa tidak ditemukan")
 	}
 
-	// 2. Check Ownership
-	if user.CreatedByID == nil || *user.CreatedByID != teacherID {
-		return errors.New("unauthorized: anda tidak berhak menghapus siswa ini")
+	// 2. Anonymize instead of hard delete so submissions & activities keep a valid owner
+	if err := anonymizeUser(studentID); err != nil {
+		return err
 	}
 
-	// 3. Delete
-	return repository.DeleteUser(studentID)
+	recordAudit(teacherID, meta, auditEntry{
+		Action:     model.AuditStudentDelete,
+		TargetType: "user",
+		TargetID:   studentID,
+		After:      map[string]interface{}{"anonymized": true},
+	})
+
+	return nil
 }
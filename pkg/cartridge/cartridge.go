@@ -0,0 +1,76 @@
+// Package cartridge menulis paket IMS Common Cartridge (1.3) dan membaca paket Common Cartridge
+// 1.x maupun SCORM 1.2 (keduanya memakai imsmanifest.xml gaya IMS Content Packaging).
+package cartridge
+
+// Format paket yang dikenali saat impor.
+type Format string
+
+const (
+	FormatCommonCartridge Format = "common_cartridge"
+	FormatSCORM           Format = "scorm"
+)
+
+type ItemKind string
+
+const (
+	ItemPage        ItemKind = "page"        // Halaman HTML di dalam paket (beserta file pendukungnya)
+	ItemFile        ItemKind = "file"        // Satu file non-HTML di dalam paket (PDF, audio, gambar, ...)
+	ItemLink        ItemKind = "link"        // Tautan web (imswl)
+	ItemAssignment  ItemKind = "assignment"  // Tugas (ekstensi assignment CC 1.3)
+	ItemUnsupported ItemKind = "unsupported" // Kuis, diskusi, LTI, dll. Hanya muncul saat impor
+)
+
+// Package adalah isi kelas yang dipetakan ke/dari paket. Module berisi item berurutan;
+// Items di level atas (di luar modul) dipakai untuk tugas yang tidak terkait modul.
+type Package struct {
+	Title       string
+	Description string
+	Modules     []Module
+	Items       []Item
+}
+
+type Module struct {
+	Title string
+	Items []Item
+}
+
+type Item struct {
+	Title string
+	Kind  ItemKind
+
+	URL        string      // ItemLink
+	Href       string      // Path file utama (halaman/file) di dalam paket
+	Files      []File      // Semua file resource, termasuk Href dan dependensinya
+	Assignment *Assignment // ItemAssignment
+
+	ResourceType string // Tipe resource asli di manifest (impor)
+	SCO          bool   // SCORM: resource adalah SCO yang butuh runtime, bukan aset biasa
+}
+
+// File di dalam paket. Saat ekspor isinya diambil dari Data, atau dari SourcePath di disk
+// jika Data kosong. Saat impor hanya Href yang terisi; isinya dibaca lewat Archive.
+type File struct {
+	Href       string
+	Data       []byte
+	SourcePath string
+}
+
+type Assignment struct {
+	Title       string
+	Instruction string // HTML
+	MaxPoints   int
+	Formats     []string // text, file, url, html
+}
+
+const (
+	manifestName = "imsmanifest.xml"
+
+	resourceWebContent = "webcontent"
+	resourceWebLink    = "imswl_xmlv1p3"
+	resourceAssignment = "assignment_xmlv1p0"
+
+	namespaceManifest   = "http://www.imsglobal.org/xsd/imsccv1p3/imscp_v1p1"
+	namespaceLOM        = "http://ltsc.ieee.org/xsd/imsccv1p3/LOM/manifest"
+	namespaceWebLink    = "http://www.imsglobal.org/xsd/imsccv1p3/imswl_v1p3"
+	namespaceAssignment = "http://www.imsglobal.org/xsd/imscc_extensions/assignment"
+)
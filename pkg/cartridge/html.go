@@ -0,0 +1,212 @@
+package cartridge
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Page adalah hasil baca longgar sebuah halaman HTML dari paket.
+type Page struct {
+	Title    string
+	Meta     map[string]string // <meta name="..." content="...">
+	Text     string            // Teks isi body
+	Sections map[string]string // Teks per elemen yang memiliki atribut id
+	Links    map[string]string // href/src per elemen yang memiliki atribut id
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "section": true, "article": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "pre": true, "blockquote": true,
+}
+
+// ParsePage membaca HTML dengan decoder XML mode tidak ketat (tag tak tertutup dan entitas HTML
+// diterima). Spasi dirapikan kecuali di dalam <pre>; isi script/style diabaikan.
+func ParsePage(data []byte) Page {
+	page := Page{Meta: map[string]string{}, Sections: map[string]string{}, Links: map[string]string{}}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	type openElement struct {
+		name string
+		id   string
+	}
+	var (
+		stack    []openElement
+		body     strings.Builder
+		sections = map[string]*strings.Builder{}
+		inTitle  bool
+		skip     int
+		pre      int
+	)
+	write := func(s string) {
+		body.WriteString(s)
+		for _, el := range stack {
+			if el.id != "" {
+				sections[el.id].WriteString(s)
+			}
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			attrs := map[string]string{}
+			for _, attr := range t.Attr {
+				attrs[strings.ToLower(attr.Name.Local)] = attr.Value
+			}
+
+			switch name {
+			case "script", "style":
+				skip++
+			case "title":
+				inTitle = true
+			case "pre":
+				pre++
+			case "meta":
+				if attrs["name"] != "" {
+					page.Meta[strings.ToLower(attrs["name"])] = attrs["content"]
+				}
+			}
+			if blockElements[name] {
+				write("\n")
+			}
+
+			id := attrs["id"]
+			if id != "" {
+				if _, ok := sections[id]; !ok {
+					sections[id] = &strings.Builder{}
+				}
+				if link := attrs["href"]; link != "" {
+					page.Links[id] = link
+				} else if link := attrs["src"]; link != "" {
+					page.Links[id] = link
+				}
+			}
+			stack = append(stack, openElement{name: name, id: id})
+
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch name {
+			case "script", "style":
+				if skip > 0 {
+					skip--
+				}
+			case "title":
+				inTitle = false
+			case "pre":
+				if pre > 0 {
+					pre--
+				}
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			if blockElements[name] && name != "br" {
+				write("\n")
+			}
+
+		case xml.CharData:
+			text := string(t)
+			switch {
+			case inTitle:
+				page.Title += text
+			case skip > 0:
+			case pre > 0:
+				write(text)
+			default:
+				write(whitespacePattern.ReplaceAllString(text, " "))
+			}
+		}
+	}
+
+	page.Title = strings.TrimSpace(page.Title)
+	page.Text = tidyLines(body.String())
+	for id, b := range sections {
+		page.Sections[id] = strings.Trim(b.String(), "\n")
+	}
+	return page
+}
+
+// tidyLines membuang spasi di ujung baris dan baris kosong berlebih.
+func tidyLines(s string) string {
+	lines := strings.Split(s, "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// PageBuilder menyusun halaman XHTML sederhana yang tetap terbaca di LMS lain (Moodle, Canvas)
+// sekaligus bisa dibaca ulang oleh ParsePage.
+type PageBuilder struct {
+	title string
+	meta  [][2]string
+	body  strings.Builder
+}
+
+func NewPage(title string) *PageBuilder {
+	p := &PageBuilder{title: title}
+	p.body.WriteString("<h1>" + html.EscapeString(title) + "</h1>\n")
+	return p
+}
+
+func (p *PageBuilder) Meta(name, content string) *PageBuilder {
+	p.meta = append(p.meta, [2]string{name, content})
+	return p
+}
+
+// Raw menambahkan potongan HTML apa adanya.
+func (p *PageBuilder) Raw(fragment string) *PageBuilder {
+	p.body.WriteString(fragment + "\n")
+	return p
+}
+
+// Section menambahkan judul dan teks yang isinya dipertahankan persis (termasuk baris baru).
+func (p *PageBuilder) Section(id, heading, text string) *PageBuilder {
+	if heading != "" {
+		p.body.WriteString("<h2>" + html.EscapeString(heading) + "</h2>\n")
+	}
+	p.body.WriteString(`<pre id="` + html.EscapeString(id) + `" style="white-space: pre-wrap; font-family: inherit;">` +
+		html.EscapeString(text) + "</pre>\n")
+	return p
+}
+
+func (p *PageBuilder) Bytes() []byte {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html xmlns=\"http://www.w3.org/1999/xhtml\">\n<head>\n<meta charset=\"utf-8\"/>\n")
+	b.WriteString("<title>" + html.EscapeString(p.title) + "</title>\n")
+	for _, m := range p.meta {
+		b.WriteString(`<meta name="` + html.EscapeString(m[0]) + `" content="` + html.EscapeString(m[1]) + "\"/>\n")
+	}
+	b.WriteString("</head>\n<body>\n")
+	b.WriteString(p.body.String())
+	b.WriteString("</body>\n</html>\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,419 @@
+package cartridge
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxArchiveEntries   = 5000
+	maxArchiveFileBytes = 200 << 20
+	maxArchiveBytes     = 1 << 30
+	maxDescriptorBytes  = 5 << 20 // imsmanifest.xml, weblink dan assignment XML
+)
+
+// Struktur manifest untuk dibaca. Tag tanpa namespace cocok dengan namespace apa pun, sehingga
+// versi CC 1.0-1.3 dan SCORM 1.2 (adlcp:scormtype) terbaca dengan struktur yang sama.
+type manifestIn struct {
+	Metadata struct {
+		Schema        string `xml:"schema"`
+		SchemaVersion string `xml:"schemaversion"`
+		Title         string `xml:"lom>general>title>string"`
+		Description   string `xml:"lom>general>description>string"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Default string  `xml:"default,attr"`
+		Items   []orgIn `xml:"organization"`
+	} `xml:"organizations"`
+	Resources struct {
+		Base  string       `xml:"base,attr"`
+		Items []resourceIn `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type orgIn struct {
+	Identifier string   `xml:"identifier,attr"`
+	Title      string   `xml:"title"`
+	Items      []itemIn `xml:"item"`
+}
+
+type itemIn struct {
+	IdentifierRef string   `xml:"identifierref,attr"`
+	Title         string   `xml:"title"`
+	Items         []itemIn `xml:"item"`
+}
+
+type resourceIn struct {
+	Identifier    string `xml:"identifier,attr"`
+	Type          string `xml:"type,attr"`
+	Href          string `xml:"href,attr"`
+	Base          string `xml:"base,attr"`
+	ScormType     string `xml:"scormtype,attr"` // SCORM 1.2
+	ScormType2004 string `xml:"scormType,attr"` // SCORM 2004
+	Files         []struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+	Dependencies []struct {
+		IdentifierRef string `xml:"identifierref,attr"`
+	} `xml:"dependency"`
+}
+
+type webLinkIn struct {
+	Title string `xml:"title"`
+	URL   struct {
+		Href string `xml:"href,attr"`
+	} `xml:"url"`
+}
+
+type assignmentIn struct {
+	Title    string `xml:"title"`
+	Text     string `xml:"text"`
+	Gradable struct {
+		PointsPossible string `xml:"points_possible,attr"`
+	} `xml:"gradable"`
+	Formats []struct {
+		Type string `xml:"type,attr"`
+	} `xml:"submission_formats>format"`
+}
+
+// Archive adalah paket yang sudah dibuka. Package berisi struktur kelas hasil pemetaan manifest;
+// isi file dibaca lewat ReadFile/Extract dengan path Href yang sama.
+type Archive struct {
+	Format  Format
+	Version string
+	Package *Package
+
+	zip       *zip.ReadCloser
+	files     map[string]*zip.File
+	extracted int64
+}
+
+// Open membuka paket .imscc/.zip, memeriksa batas ukuran dan memetakan manifest ke Package.
+// Organisasi pertama (atau default) dipakai: item level atas menjadi modul dan item di bawahnya
+// (berapa pun kedalamannya) menjadi isi modul tersebut.
+func Open(zipPath string) (*Archive, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, errors.New("paket tidak valid: file bukan arsip zip")
+	}
+
+	a := &Archive{zip: zr, files: make(map[string]*zip.File)}
+	if len(zr.File) > maxArchiveEntries {
+		zr.Close()
+		return nil, errors.New("paket tidak valid: jumlah file melebihi batas " + strconv.Itoa(maxArchiveEntries))
+	}
+	var total uint64
+	for _, f := range zr.File {
+		total += f.UncompressedSize64
+		name, ok := cleanHref(f.Name)
+		if !ok {
+			zr.Close()
+			return nil, errors.New("paket tidak valid: path file tidak aman (" + f.Name + ")")
+		}
+		a.files[name] = f
+	}
+	if total > maxArchiveBytes {
+		zr.Close()
+		return nil, errors.New("paket tidak valid: ukuran isi paket melebihi 1GB")
+	}
+
+	if err := a.readManifest(); err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Archive) Close() error {
+	return a.zip.Close()
+}
+
+func (a *Archive) readManifest() error {
+	data, err := a.readLimited(manifestName, maxDescriptorBytes)
+	if err != nil {
+		return errors.New("paket tidak valid: imsmanifest.xml tidak ditemukan")
+	}
+
+	var m manifestIn
+	if err := xml.Unmarshal(data, &m); err != nil {
+		return errors.New("paket tidak valid: imsmanifest.xml gagal dibaca (" + err.Error() + ")")
+	}
+
+	a.Format = FormatCommonCartridge
+	a.Version = m.Metadata.SchemaVersion
+	if strings.Contains(strings.ToUpper(m.Metadata.Schema), "SCORM") {
+		a.Format = FormatSCORM
+	}
+	resources := make(map[string]resourceIn, len(m.Resources.Items))
+	for _, r := range m.Resources.Items {
+		if r.ScormType != "" || r.ScormType2004 != "" {
+			a.Format = FormatSCORM
+		}
+		r.Base = joinBase(m.Resources.Base, r.Base)
+		resources[r.Identifier] = r
+	}
+
+	if len(m.Organizations.Items) == 0 {
+		return errors.New("paket tidak valid: manifest tidak memiliki organisasi (struktur kelas)")
+	}
+	org := m.Organizations.Items[0]
+	for _, o := range m.Organizations.Items {
+		if o.Identifier == m.Organizations.Default {
+			org = o
+		}
+	}
+
+	p := &Package{Title: strings.TrimSpace(m.Metadata.Title), Description: strings.TrimSpace(m.Metadata.Description)}
+	if p.Title == "" {
+		p.Title = strings.TrimSpace(org.Title)
+	}
+
+	top := org.Items
+	// Common Cartridge membungkus seluruh isi dalam satu item root tanpa judul
+	if len(top) == 1 && top[0].IdentifierRef == "" && strings.TrimSpace(top[0].Title) == "" {
+		top = top[0].Items
+	}
+
+	for _, it := range top {
+		if it.IdentifierRef != "" {
+			item := a.mapItem(it, resources)
+			if item.Kind == ItemAssignment {
+				p.Items = append(p.Items, item)
+				continue
+			}
+			// Item tunggal di level atas (mis. SCO tunggal SCORM) menjadi modul berisi dirinya sendiri
+			module := Module{Title: item.Title, Items: []Item{item}}
+			module.Items = append(module.Items, a.flattenItems(it.Items, resources)...)
+			p.Modules = append(p.Modules, module)
+			continue
+		}
+		p.Modules = append(p.Modules, Module{
+			Title: strings.TrimSpace(it.Title),
+			Items: a.flattenItems(it.Items, resources),
+		})
+	}
+
+	a.Package = p
+	return nil
+}
+
+func (a *Archive) flattenItems(items []itemIn, resources map[string]resourceIn) []Item {
+	var result []Item
+	for _, it := range items {
+		if it.IdentifierRef != "" {
+			result = append(result, a.mapItem(it, resources))
+		}
+		result = append(result, a.flattenItems(it.Items, resources)...)
+	}
+	return result
+}
+
+func (a *Archive) mapItem(it itemIn, resources map[string]resourceIn) Item {
+	item := Item{Title: strings.TrimSpace(it.Title), Kind: ItemUnsupported}
+	r, ok := resources[it.IdentifierRef]
+	if !ok {
+		return item
+	}
+	item.ResourceType = r.Type
+	item.SCO = strings.EqualFold(r.ScormType, "sco") || strings.EqualFold(r.ScormType2004, "sco")
+
+	// File resource beserta file dari dependensinya (SCORM memakai dependency untuk aset bersama)
+	seen := make(map[string]bool)
+	var collect func(r resourceIn, depth int)
+	collect = func(r resourceIn, depth int) {
+		for _, f := range r.Files {
+			if href, ok := cleanHref(joinBase(r.Base, f.Href)); ok && !seen[href] {
+				seen[href] = true
+				item.Files = append(item.Files, File{Href: href})
+			}
+		}
+		if depth > 10 {
+			return
+		}
+		for _, dep := range r.Dependencies {
+			if d, ok := resources[dep.IdentifierRef]; ok {
+				collect(d, depth+1)
+			}
+		}
+	}
+	collect(r, 0)
+
+	resourceType := strings.ToLower(r.Type)
+	switch {
+	case strings.HasPrefix(resourceType, "imswl_"):
+		if len(item.Files) == 0 {
+			return item
+		}
+		var link webLinkIn
+		if data, err := a.readLimited(item.Files[0].Href, maxDescriptorBytes); err == nil && xml.Unmarshal(data, &link) == nil {
+			item.Kind = ItemLink
+			item.URL = strings.TrimSpace(link.URL.Href)
+			if item.Title == "" {
+				item.Title = strings.TrimSpace(link.Title)
+			}
+		}
+
+	case strings.HasPrefix(resourceType, "assignment_"):
+		if len(item.Files) == 0 {
+			return item
+		}
+		var in assignmentIn
+		if data, err := a.readLimited(item.Files[0].Href, maxDescriptorBytes); err == nil && xml.Unmarshal(data, &in) == nil {
+			points, _ := strconv.Atoi(strings.TrimSpace(in.Gradable.PointsPossible))
+			assignment := &Assignment{Title: strings.TrimSpace(in.Title), Instruction: strings.TrimSpace(in.Text), MaxPoints: points}
+			for _, f := range in.Formats {
+				assignment.Formats = append(assignment.Formats, strings.ToLower(f.Type))
+			}
+			if item.Title == "" {
+				item.Title = assignment.Title
+			}
+			item.Kind = ItemAssignment
+			item.Assignment = assignment
+		}
+
+	case resourceType == resourceWebContent || resourceType == "":
+		href := joinBase(r.Base, r.Href)
+		if r.Href == "" && len(item.Files) > 0 {
+			href = item.Files[0].Href
+		}
+		href, ok := cleanHref(href)
+		if !ok || href == "" {
+			return item
+		}
+		item.Href = href
+		if !seen[href] {
+			item.Files = append([]File{{Href: href}}, item.Files...)
+		}
+		item.Kind = ItemFile
+		if ext := strings.ToLower(path.Ext(href)); ext == ".html" || ext == ".htm" || ext == ".xhtml" {
+			item.Kind = ItemPage
+		}
+	}
+
+	return item
+}
+
+// ReadFile membaca isi satu file paket (maksimal 200MB).
+func (a *Archive) ReadFile(href string) ([]byte, error) {
+	return a.readLimited(href, maxArchiveFileBytes)
+}
+
+func (a *Archive) readLimited(href string, limit int64) ([]byte, error) {
+	rc, err := a.open(href)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.New("file " + href + " melebihi batas ukuran")
+	}
+	return data, nil
+}
+
+func (a *Archive) open(href string) (io.ReadCloser, error) {
+	name, ok := cleanHref(href)
+	if !ok {
+		return nil, errors.New("path file tidak aman: " + href)
+	}
+	f, ok := a.files[name]
+	if !ok {
+		return nil, errors.New("file " + href + " tidak ada di dalam paket")
+	}
+	return f.Open()
+}
+
+// Has memberi tahu apakah file href ada di dalam paket.
+func (a *Archive) Has(href string) bool {
+	name, ok := cleanHref(href)
+	if !ok {
+		return false
+	}
+	_, exists := a.files[name]
+	return exists
+}
+
+// Extract menyalin satu file paket ke destDir dengan mempertahankan path relatifnya, sehingga
+// tautan relatif antar halaman tetap berfungsi. Mengembalikan path file yang ditulis.
+func (a *Archive) Extract(href, destDir string) (string, error) {
+	name, ok := cleanHref(href)
+	if !ok {
+		return "", errors.New("path file tidak aman: " + href)
+	}
+	f, ok := a.files[name]
+	if !ok {
+		return "", errors.New("file " + href + " tidak ada di dalam paket")
+	}
+	if a.extracted+int64(f.UncompressedSize64) > maxArchiveBytes {
+		return "", errors.New("ukuran file yang diekstrak melebihi batas")
+	}
+
+	target := filepath.Join(destDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	written, err := io.Copy(out, io.LimitReader(rc, maxArchiveFileBytes+1))
+	a.extracted += written
+	if err != nil {
+		return "", err
+	}
+	if written > maxArchiveFileBytes {
+		return "", errors.New("file " + href + " melebihi batas 200MB")
+	}
+	return target, nil
+}
+
+// cleanHref menormalkan path di dalam paket dan menolak path absolut atau yang keluar dari
+// root paket (zip slip).
+func cleanHref(href string) (string, bool) {
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		href = href[:i]
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	href = strings.ReplaceAll(href, "\\", "/")
+	if href == "" {
+		return "", true
+	}
+	if strings.HasPrefix(href, "/") || strings.Contains(href, ":") {
+		return "", false
+	}
+	cleaned := path.Clean(href)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
+
+func joinBase(base, href string) string {
+	if base == "" || href == "" {
+		return href
+	}
+	return strings.TrimSuffix(base, "/") + "/" + href
+}
@@ -0,0 +1,34 @@
+package cartridge
+
+import "testing"
+
+func TestCleanHref(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"", "", true},
+		{"web/page.html", "web/page.html", true},
+		{"./web//a/../page.html", "web/page.html", true},
+		{"web/page.html?x=1#top", "web/page.html", true},
+		{"web/a%20b.html", "web/a b.html", true},
+		{"web\\sub\\page.html", "web/sub/page.html", true},
+		{"a/../../etc/passwd", "", false},
+		{"..", "", false},
+		{"%2e%2e/secret", "", false},
+		{"..\\..\\secret", "", false},
+		{"/etc/passwd", "", false},
+		{"\\windows\\system.ini", "", false},
+		{"C:/windows/system.ini", "", false},
+		{"http://example.com/x", "", false},
+		{"javascript:alert(1)", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := cleanHref(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("cleanHref(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
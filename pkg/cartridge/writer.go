@@ -0,0 +1,245 @@
+package cartridge
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// Struktur manifest untuk ditulis. Prefix namespace ditulis langsung di nama elemen karena
+// encoding/xml tidak bisa memilih prefix sendiri.
+type manifestOut struct {
+	XMLName       xml.Name         `xml:"manifest"`
+	Identifier    string           `xml:"identifier,attr"`
+	Xmlns         string           `xml:"xmlns,attr"`
+	XmlnsLOM      string           `xml:"xmlns:lomimscc,attr"`
+	Metadata      metadataOut      `xml:"metadata"`
+	Organizations organizationsOut `xml:"organizations"`
+	Resources     []resourceOut    `xml:"resources>resource"`
+}
+
+type metadataOut struct {
+	Schema        string `xml:"schema"`
+	SchemaVersion string `xml:"schemaversion"`
+	Title         string `xml:"lomimscc:lom>lomimscc:general>lomimscc:title>lomimscc:string"`
+	Description   string `xml:"lomimscc:lom>lomimscc:general>lomimscc:description>lomimscc:string,omitempty"`
+}
+
+type organizationsOut struct {
+	Organization struct {
+		Identifier string  `xml:"identifier,attr"`
+		Structure  string  `xml:"structure,attr"`
+		Root       itemOut `xml:"item"`
+	} `xml:"organization"`
+}
+
+type itemOut struct {
+	Identifier    string    `xml:"identifier,attr"`
+	IdentifierRef string    `xml:"identifierref,attr,omitempty"`
+	Title         string    `xml:"title,omitempty"`
+	Items         []itemOut `xml:"item"`
+}
+
+type resourceOut struct {
+	Identifier string    `xml:"identifier,attr"`
+	Type       string    `xml:"type,attr"`
+	Href       string    `xml:"href,attr,omitempty"`
+	Files      []fileOut `xml:"file"`
+}
+
+type fileOut struct {
+	Href string `xml:"href,attr"`
+}
+
+type webLinkOut struct {
+	XMLName xml.Name `xml:"webLink"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Title   string   `xml:"title"`
+	URL     struct {
+		Href   string `xml:"href,attr"`
+		Target string `xml:"target,attr"`
+	} `xml:"url"`
+}
+
+type assignmentOut struct {
+	XMLName    xml.Name `xml:"assignment"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	Identifier string   `xml:"identifier,attr"`
+	Title      string   `xml:"title"`
+	Text       struct {
+		Type  string `xml:"texttype,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"text"`
+	Gradable struct {
+		PointsPossible int    `xml:"points_possible,attr"`
+		Value          string `xml:",chardata"`
+	} `xml:"gradable"`
+	Formats []struct {
+		Type string `xml:"type,attr"`
+	} `xml:"submission_formats>format"`
+}
+
+// Write menulis paket sebagai IMS Common Cartridge 1.3 (.imscc). Halaman dan file ditulis apa
+// adanya sesuai Href/Files; tautan dan tugas dibuatkan file XML-nya sendiri.
+func Write(w io.Writer, p *Package) error {
+	zw := zip.NewWriter(w)
+
+	manifest := manifestOut{
+		Identifier: "MANIFEST_1",
+		Xmlns:      namespaceManifest,
+		XmlnsLOM:   namespaceLOM,
+		Metadata: metadataOut{
+			Schema:        "IMS Common Cartridge",
+			SchemaVersion: "1.3.0",
+			Title:         p.Title,
+			Description:   p.Description,
+		},
+	}
+	manifest.Organizations.Organization.Identifier = "ORG_1"
+	manifest.Organizations.Organization.Structure = "rooted-hierarchy"
+	manifest.Organizations.Organization.Root.Identifier = "ROOT"
+
+	resourceCount := 0
+	addItem := func(item Item) (itemOut, error) {
+		resourceCount++
+		id := "R_" + strconv.Itoa(resourceCount)
+		out := itemOut{Identifier: "I_" + id, IdentifierRef: id, Title: item.Title}
+
+		resource, err := writeResource(zw, id, item)
+		if err != nil {
+			return out, err
+		}
+		manifest.Resources = append(manifest.Resources, resource)
+		return out, nil
+	}
+
+	root := &manifest.Organizations.Organization.Root
+	for i, module := range p.Modules {
+		moduleItem := itemOut{Identifier: fmt.Sprintf("M_%d", i+1), Title: module.Title}
+		for _, item := range module.Items {
+			out, err := addItem(item)
+			if err != nil {
+				return err
+			}
+			moduleItem.Items = append(moduleItem.Items, out)
+		}
+		root.Items = append(root.Items, moduleItem)
+	}
+	for _, item := range p.Items {
+		out, err := addItem(item)
+		if err != nil {
+			return err
+		}
+		root.Items = append(root.Items, out)
+	}
+
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mw, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(mw)
+	enc.Indent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeResource(zw *zip.Writer, id string, item Item) (resourceOut, error) {
+	resource := resourceOut{Identifier: id}
+
+	switch item.Kind {
+	case ItemLink:
+		link := webLinkOut{Xmlns: namespaceWebLink, Title: item.Title}
+		link.URL.Href = item.URL
+		link.URL.Target = "_blank"
+		href := id + "/weblink.xml"
+		if err := writeXMLFile(zw, href, link); err != nil {
+			return resource, err
+		}
+		resource.Type = resourceWebLink
+		resource.Files = []fileOut{{Href: href}}
+
+	case ItemAssignment:
+		a := item.Assignment
+		if a == nil {
+			a = &Assignment{Title: item.Title}
+		}
+		out := assignmentOut{Xmlns: namespaceAssignment, Identifier: id, Title: a.Title}
+		out.Text.Type = "text/html"
+		out.Text.Value = a.Instruction
+		out.Gradable.PointsPossible = a.MaxPoints
+		out.Gradable.Value = strconv.FormatBool(a.MaxPoints > 0)
+		for _, format := range a.Formats {
+			out.Formats = append(out.Formats, struct {
+				Type string `xml:"type,attr"`
+			}{Type: format})
+		}
+		href := id + "/assignment.xml"
+		if err := writeXMLFile(zw, href, out); err != nil {
+			return resource, err
+		}
+		resource.Type = resourceAssignment
+		resource.Files = []fileOut{{Href: href}}
+
+	case ItemPage, ItemFile:
+		resource.Type = resourceWebContent
+		// Path di manifest berupa URL relatif, sedangkan nama entri zip ditulis apa adanya
+		resource.Href = escapeHref(item.Href)
+		for _, file := range item.Files {
+			if err := writeFile(zw, file); err != nil {
+				return resource, err
+			}
+			resource.Files = append(resource.Files, fileOut{Href: escapeHref(file.Href)})
+		}
+
+	default:
+		return resource, fmt.Errorf("jenis item %q tidak bisa diekspor", item.Kind)
+	}
+
+	return resource, nil
+}
+
+func escapeHref(href string) string {
+	return (&url.URL{Path: href}).EscapedPath()
+}
+
+func writeXMLFile(zw *zip.Writer, href string, v interface{}) error {
+	w, err := zw.Create(href)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeFile(zw *zip.Writer, file File) error {
+	w, err := zw.Create(file.Href)
+	if err != nil {
+		return err
+	}
+	if file.Data != nil || file.SourcePath == "" {
+		_, err = w.Write(file.Data)
+		return err
+	}
+
+	f, err := os.Open(file.SourcePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
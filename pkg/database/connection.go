@@ -65,6 +65,14 @@ func Migrate() {
 			&model.Subtest{},
 			&model.AccessibilityProfile{},
 			&model.Activity{},
+			&model.UserSession{},
+			&model.RefreshToken{},
+			&model.PasswordResetToken{},
+			&model.LoginAttempt{},
+			&model.TwoFactorRecoveryCode{},
+			&model.UserIdentity{},
+			&model.SSOLoginState{},
+			&model.AccountDeletionRequest{},
 		)
 		if err != nil {
 			log.Fatal("Failed to migrate Step 1 (Users):", err)
@@ -73,6 +81,10 @@ func Migrate() {
 		err = DB.AutoMigrate(
 			&model.Course{},
 			&model.Module{},
+			&model.CourseStaff{},
+			&model.EnrollmentRequest{},
+			&model.CourseInvitation{},
+			&model.CourseTemplate{},
 		)
 		if err != nil {
 			log.Fatal("Failed to migrate Step 2 (Courses):", err)
@@ -95,13 +107,56 @@ func Migrate() {
 			&model.QuestionReport{},
 			&model.Submission{},
 			&model.MaterialCompletion{},
+			&model.MaterialProgress{},
+			&model.MaterialVersion{},
+			&model.Announcement{},
+			&model.AnnouncementAttachment{},
+			&model.AnnouncementRead{},
+			&model.DiscussionThread{},
+			&model.DiscussionReply{},
+			&model.DiscussionMention{},
+			&model.MaterialAnnotation{},
+			&model.MaterialBookmark{},
 		)
 		if err != nil {
 			log.Fatal("Failed to migrate Step 4 (Features):", err)
 		}
 
+		err = DB.AutoMigrate(
+			&model.AuditLog{},
+		)
+		if err != nil {
+			log.Fatal("Failed to migrate Step 5 (Audit):", err)
+		}
+
+		if err := protectAuditLog(); err != nil {
+			log.Fatal("Failed to protect audit log:", err)
+		}
+
 		log.Println("Database migration completed successfully")
 	} else {
 		log.Println("Production mode: Skipping AutoMigrate to save startup time.")
 	}
 }
+
+// protectAuditLog membuat audit_logs append-only di level database, sehingga baris tidak bisa
+// diubah atau dihapus walaupun tidak lewat repository.
+func protectAuditLog() error {
+	if err := DB.Exec(`
+		CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append-only';
+		END;
+		$$ LANGUAGE plpgsql`).Error; err != nil {
+		return err
+	}
+
+	if err := DB.Exec(`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`).Error; err != nil {
+		return err
+	}
+
+	return DB.Exec(`
+		CREATE TRIGGER audit_logs_append_only
+		BEFORE UPDATE OR DELETE ON audit_logs
+		FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()`).Error
+}
@@ -6,12 +6,14 @@ import (
 	"ramah-disabilitas-be/pkg/utils"
 )
 
+const defaultAdminPassword = "admin123"
+
 func SeedAdmin() {
 	var count int64
 	DB.Model(&model.User{}).Where("role = ?", model.RoleAdmin).Count(&count)
 
 	if count == 0 {
-		hashedPassword, err := utils.HashPassword("admin123")
+		hashedPassword, err := utils.HashPassword(defaultAdminPassword)
 		if err != nil {
 			log.Fatalf("Gagal melakukan hash password admin: %v", err)
 		}
@@ -21,14 +23,35 @@ func SeedAdmin() {
 			Email:    "admin@testclash.com",
 			Password: hashedPassword,
 			Role:     model.RoleAdmin,
+			// Password bawaan sudah diketahui umum, wajib diganti saat login pertama
+			MustChangePassword: true,
 		}
 
 		if err := DB.Create(&admin).Error; err != nil {
 			log.Printf("Gagal membuat user admin: %v", err)
 		} else {
-			log.Println("User admin berhasil dibuat (email: admin@testclash.com, password: admin123)")
+			log.Println("User admin berhasil dibuat (email: admin@testclash.com, password: admin123, wajib ganti password saat login pertama)")
 		}
 	} else {
 		log.Println("User admin sudah ada, seeding dilewati.")
+		flagDefaultAdminPassword()
+	}
+}
+
+// flagDefaultAdminPassword mewajibkan ganti password untuk admin yang dibuat sebelum flag
+// must_change_password ada dan masih memakai password bawaan.
+func flagDefaultAdminPassword() {
+	var admins []model.User
+	DB.Where("role = ? AND must_change_password = ?", model.RoleAdmin, false).Find(&admins)
+
+	for _, admin := range admins {
+		if !utils.CheckPasswordHash(defaultAdminPassword, admin.Password) {
+			continue
+		}
+		if err := DB.Model(&model.User{}).Where("id = ?", admin.ID).Update("must_change_password", true).Error; err != nil {
+			log.Printf("Gagal menandai admin %s wajib ganti password: %v", admin.Email, err)
+			continue
+		}
+		log.Printf("Admin %s masih memakai password bawaan, wajib ganti password saat login berikutnya", admin.Email)
 	}
 }
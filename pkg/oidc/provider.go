@@ -0,0 +1,163 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Provider holds the static configuration of one OpenID Connect identity provider.
+// Providers are configured through environment variables so new campuses can be
+// plugged in without code changes:
+//
+//	OIDC_PROVIDERS=campus
+//	OIDC_CAMPUS_ISSUER=https://sso.example.ac.id
+//	OIDC_CAMPUS_CLIENT_ID=...
+//	OIDC_CAMPUS_CLIENT_SECRET=...            (optional, public clients rely on PKCE only)
+//	OIDC_CAMPUS_REDIRECT_URL=https://app.example.ac.id/sso/callback
+//	OIDC_CAMPUS_DISPLAY_NAME=SSO Kampus       (optional)
+//	OIDC_CAMPUS_SCOPES=openid email profile   (optional)
+//	OIDC_CAMPUS_ROLE_CLAIM=role               (optional)
+//	OIDC_CAMPUS_ROLE_MAP=mahasiswa:student,dosen:lecturer
+//	OIDC_CAMPUS_DEFAULT_ROLE=student          (optional)
+//	OIDC_CAMPUS_AUTO_PROVISION=true           (optional)
+//	OIDC_CAMPUS_ALLOWED_DOMAINS=example.ac.id (optional)
+type Provider struct {
+	Name           string
+	DisplayName    string
+	Issuer         string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	Scopes         []string
+	RoleClaim      string
+	RoleMap        map[string]string
+	DefaultRole    string
+	AutoProvision  bool
+	AllowedDomains []string
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]interface{}
+}
+
+var (
+	providers     map[string]*Provider
+	providersOnce sync.Once
+)
+
+// GetProvider returns the configured provider with the given name.
+func GetProvider(name string) (*Provider, bool) {
+	loadProviders()
+	p, ok := providers[strings.ToLower(name)]
+	return p, ok
+}
+
+// Providers returns every configured provider in the order listed in OIDC_PROVIDERS.
+func Providers() []*Provider {
+	loadProviders()
+	var list []*Provider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		if p, ok := providers[strings.ToLower(strings.TrimSpace(name))]; ok {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+func loadProviders() {
+	providersOnce.Do(func() {
+		providers = map[string]*Provider{}
+		for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if p := providerFromEnv(name); p != nil {
+				providers[name] = p
+			}
+		}
+	})
+}
+
+func providerFromEnv(name string) *Provider {
+	prefix := "OIDC_" + strings.ToUpper(name) + "_"
+	env := func(key, fallback string) string {
+		if v := strings.TrimSpace(os.Getenv(prefix + key)); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	p := &Provider{
+		Name:          name,
+		DisplayName:   env("DISPLAY_NAME", name),
+		Issuer:        strings.TrimRight(env("ISSUER", ""), "/"),
+		ClientID:      env("CLIENT_ID", ""),
+		ClientSecret:  env("CLIENT_SECRET", ""),
+		RedirectURL:   env("REDIRECT_URL", ""),
+		Scopes:        strings.Fields(env("SCOPES", "openid email profile")),
+		RoleClaim:     env("ROLE_CLAIM", "role"),
+		RoleMap:       map[string]string{},
+		DefaultRole:   env("DEFAULT_ROLE", "student"),
+		AutoProvision: env("AUTO_PROVISION", "true") == "true",
+	}
+	if p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(env("ROLE_MAP", ""), ",") {
+		claim, role, ok := strings.Cut(pair, ":")
+		if ok {
+			p.RoleMap[strings.TrimSpace(claim)] = strings.TrimSpace(role)
+		}
+	}
+
+	for _, domain := range strings.Split(env("ALLOWED_DOMAINS", ""), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			p.AllowedDomains = append(p.AllowedDomains, domain)
+		}
+	}
+
+	return p
+}
+
+// MapRole translates the provider's role claim (a string or a list of strings)
+// into an application role. The first value found in RoleMap wins.
+func (p *Provider) MapRole(claims map[string]interface{}) string {
+	var values []string
+	switch v := claims[p.RoleClaim].(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	for _, v := range values {
+		if role, ok := p.RoleMap[v]; ok {
+			return role
+		}
+	}
+	return p.DefaultRole
+}
+
+// EmailAllowed reports whether the email domain is accepted by this provider.
+func (p *Provider) EmailAllowed(email string) bool {
+	if len(p.AllowedDomains) == 0 {
+		return true
+	}
+	_, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok {
+		return false
+	}
+	for _, allowed := range p.AllowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -8,10 +8,6 @@ import (
 )
 
 func SendVerificationEmail(toEmail, token string) error {
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-	smtpUser := os.Getenv("SMTP_USER")
-	smtpPass := os.Getenv("SMTP_PASSWORD")
 	baseURL := os.Getenv("BASE_URL") // URL of the frontend or backend depending on where the verify link points.
 	// Currently pointing to backend for simplicity as requested, but standard is frontend.
 	// The user request: "berikan di response ketika abis regist user disuruh mengecek email verifikasi"
@@ -24,11 +20,47 @@ func SendVerificationEmail(toEmail, token string) error {
 
 	verificationLink := fmt.Sprintf("%s/api/v1/auth/verify-email?token=%s", baseURL, token)
 
+	body := "Halo,\r\n\r\n" +
+		"Terima kasih telah mendaftar. Silakan klik tautan di bawah ini untuk memverifikasi email Anda:\r\n" +
+		verificationLink + "\r\n\r\n" +
+		"Jika Anda tidak merasa mendaftar, abaikan email ini.\r\n"
+
+	return sendEmail(toEmail, "Verifikasi Email Anda", body, "Verification Link", verificationLink)
+}
+
+func SendPasswordResetEmail(toEmail, token string) error {
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s", frontendURL(), token)
+
+	body := "Halo,\r\n\r\n" +
+		"Kami menerima permintaan untuk mengatur ulang password akun Anda. Silakan klik tautan di bawah ini (berlaku 1 jam):\r\n" +
+		resetLink + "\r\n\r\n" +
+		"Jika Anda tidak meminta reset password, abaikan email ini. Password Anda tidak akan berubah.\r\n"
+
+	return sendEmail(toEmail, "Reset Password Akun Anda", body, "Password Reset Link", resetLink)
+}
+
+// frontendURL adalah alamat aplikasi web yang menampilkan halaman form (mis. reset password).
+func frontendURL() string {
+	url := os.Getenv("FRONTEND_URL")
+	if url == "" {
+		url = "http://localhost:5173" // Default dev
+	}
+	return url
+}
+
+// sendEmail mengirim email lewat SMTP, atau mencetaknya ke log jika SMTP belum dikonfigurasi.
+func sendEmail(toEmail, subject, body, linkLabel, link string) error {
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort := os.Getenv("SMTP_PORT")
+	smtpUser := os.Getenv("SMTP_USER")
+	smtpPass := os.Getenv("SMTP_PASSWORD")
+
 	if smtpHost == "" || smtpUser == "" {
 		// Mock email sending for dev environment without SMTP credentials
 		log.Printf("==================================================\n")
 		log.Printf("[MOCK EMAIL] To: %s\n", toEmail)
-		log.Printf("[MOCK EMAIL] Verification Link: %s\n", verificationLink)
+		log.Printf("[MOCK EMAIL] Subject: %s\n", subject)
+		log.Printf("[MOCK EMAIL] %s: %s\n", linkLabel, link)
 		log.Printf("==================================================\n")
 		return nil
 	}
@@ -36,12 +68,9 @@ func SendVerificationEmail(toEmail, token string) error {
 	auth := smtp.PlainAuth("", smtpUser, smtpPass, smtpHost)
 
 	msg := []byte(fmt.Sprintf("To: %s\r\n"+
-		"Subject: Verifikasi Email Anda\r\n"+
+		"Subject: %s\r\n"+
 		"\r\n"+
-		"Halo,\r\n\r\n"+
-		"Terima kasih telah mendaftar. Silakan klik tautan di bawah ini untuk memverifikasi email Anda:\r\n"+
-		"%s\r\n\r\n"+
-		"Jika Anda tidak merasa mendaftar, abaikan email ini.\r\n", toEmail, verificationLink))
+		"%s", toEmail, subject, body))
 
 	addr := fmt.Sprintf("%s:%s", smtpHost, smtpPort)
 	err := smtp.SendMail(addr, auth, smtpUser, []string{toEmail}, msg)
@@ -51,3 +80,30 @@ func SendVerificationEmail(toEmail, token string) error {
 
 	return nil
 }
+
+func SendAccountDeletionEmail(toEmail, token string) error {
+	confirmLink := fmt.Sprintf("%s/confirm-account-deletion?token=%s", frontendURL(), token)
+
+	body := "Halo,\r\n\r\n" +
+		"Kami menerima permintaan untuk menghapus akun Anda. Klik tautan di bawah ini (berlaku 24 jam) untuk mengonfirmasi:\r\n" +
+		confirmLink + "\r\n\r\n" +
+		"Setelah dikonfirmasi, data pribadi Anda dihapus permanen dan akun tidak dapat dipulihkan. " +
+		"Nilai tugas tetap tersimpan tanpa identitas Anda untuk keperluan rekap kelas.\r\n\r\n" +
+		"Jika Anda tidak meminta penghapusan akun, abaikan email ini dan segera ganti password Anda.\r\n"
+
+	return sendEmail(toEmail, "Konfirmasi Penghapusan Akun", body, "Account Deletion Link", confirmLink)
+}
+
+func SendCourseInvitationEmail(toEmail, token, courseTitle, inviterName string) error {
+	inviteLink := fmt.Sprintf("%s/invitations?token=%s", frontendURL(), token)
+
+	body := "Halo,\r\n\r\n" +
+		fmt.Sprintf("%s mengundang Anda untuk bergabung ke kelas \"%s\". ", inviterName, courseTitle) +
+		"Klik tautan di bawah ini (berlaku 7 hari) untuk menerima undangan:\r\n" +
+		inviteLink + "\r\n\r\n" +
+		"Jika Anda sudah punya akun, masuk dengan email ini untuk bergabung. " +
+		"Jika belum, Anda bisa langsung membuat akun dari tautan tersebut.\r\n\r\n" +
+		"Jika Anda tidak mengenal pengirim undangan ini, abaikan email ini.\r\n"
+
+	return sendEmail(toEmail, "Undangan Bergabung ke Kelas", body, "Course Invitation Link", inviteLink)
+}
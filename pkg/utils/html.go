@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Tag rich text yang boleh disimpan. Atribut selain href pada <a> selalu dibuang.
+var allowedHTMLTags = map[string]bool{
+	"p": true, "br": true, "strong": true, "b": true, "em": true, "i": true, "u": true, "s": true,
+	"ul": true, "ol": true, "li": true, "a": true, "blockquote": true, "code": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var htmlBlockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var htmlWhitespace = regexp.MustCompile(`\s+`)
+
+// htmlTokens membaca HTML dengan tokenizer HTML5 (golang.org/x/net/html), sehingga "<" di teks,
+// entitas, atribut tanpa tanda kutip dan tag void diperlakukan seperti di browser. Isi setelah
+// markup yang rusak tidak ikut terbuang.
+func htmlTokens(s string, fn func(html.Token)) {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		if z.Next() == html.ErrorToken {
+			return
+		}
+		fn(z.Token())
+	}
+}
+
+// SanitizeHTML menyaring rich text dari editor: hanya tag format dasar yang dipertahankan,
+// script/style dibuang beserta isinya, dan tautan hanya boleh http(s) atau mailto.
+func SanitizeHTML(s string) string {
+	var out bytes.Buffer
+	skip := 0
+	var open []string
+
+	htmlTokens(s, func(tok html.Token) {
+		switch tok.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name := tok.Data
+			if name == "script" || name == "style" {
+				if tok.Type == html.StartTagToken {
+					skip++
+				}
+				return
+			}
+			if skip > 0 || !allowedHTMLTags[name] {
+				return
+			}
+			if name == "br" {
+				out.WriteString("<br>")
+				return
+			}
+			// <li> dan <p> tanpa tag penutup ditutup oleh elemen sejenis berikutnya
+			if (name == "li" || name == "p") && len(open) > 0 && open[len(open)-1] == name {
+				out.WriteString("</" + name + ">")
+				open = open[:len(open)-1]
+			}
+			out.WriteString("<" + name)
+			if name == "a" {
+				for _, attr := range tok.Attr {
+					if attr.Key == "href" && isSafeLink(attr.Val) {
+						out.WriteString(` href="` + html.EscapeString(attr.Val) + `" rel="noopener noreferrer"`)
+						break
+					}
+				}
+			}
+			out.WriteString(">")
+			if tok.Type == html.SelfClosingTagToken {
+				out.WriteString("</" + name + ">")
+				return
+			}
+			open = append(open, name)
+		case html.EndTagToken:
+			name := tok.Data
+			if name == "script" || name == "style" {
+				if skip > 0 {
+					skip--
+				}
+				return
+			}
+			if skip > 0 || name == "br" || !allowedHTMLTags[name] {
+				return
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					for j := len(open) - 1; j >= i; j-- {
+						out.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				out.WriteString(html.EscapeString(tok.Data))
+			}
+		}
+	})
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+	return strings.TrimSpace(out.String())
+}
+
+func isSafeLink(link string) bool {
+	lower := strings.ToLower(strings.TrimSpace(link))
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
+
+// HTMLToText mengubah rich text menjadi teks polos untuk screen reader dan TTS. Elemen blok
+// menjadi baris baru dan spasi berlebih dirapikan.
+func HTMLToText(s string) string {
+	var out strings.Builder
+	skip := 0
+
+	htmlTokens(s, func(tok html.Token) {
+		switch tok.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name := tok.Data
+			if name == "script" || name == "style" {
+				if tok.Type == html.StartTagToken {
+					skip++
+				}
+			} else if name == "li" {
+				out.WriteString("\n- ")
+			} else if htmlBlockTags[name] {
+				out.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name := tok.Data
+			if (name == "script" || name == "style") && skip > 0 {
+				skip--
+			} else if htmlBlockTags[name] && name != "br" {
+				out.WriteString("\n")
+			}
+		case html.TextToken:
+			if skip == 0 {
+				out.WriteString(htmlWhitespace.ReplaceAllString(tok.Data, " "))
+			}
+		}
+	})
+
+	var lines []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" && line != "-" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,56 @@
+package utils
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"teks dengan kurang dari", "Nilai 5 < 6 tetap utuh", "Nilai 5 &lt; 6 tetap utuh"},
+		{"ampersand mentah", "Tugas A & B", "Tugas A &amp; B"},
+		{"entitas html", "Caf&eacute; &lt;b&gt;", "Café &lt;b&gt;"},
+		{"atribut tanpa kutip", `<p>a<img src=x onerror=alert(1)>b</p><p>sesudah</p>`, "<p>ab</p><p>sesudah</p>"},
+		{"tag void", "baris<br>kedua<br/>ketiga <hr>akhir", "baris<br>kedua<br>ketiga akhir"},
+		{"script dibuang", "<p>a<script>alert('<p>x</p>')</script>b</p>", "<p>ab</p>"},
+		{"style dibuang", "<style>p{color:red}</style><em>x</em>", "<em>x</em>"},
+		{"tautan aman", `<a href=https://example.com/a?b=1&c=2 onclick="x()">tautan</a>`, `<a href="https://example.com/a?b=1&amp;c=2" rel="noopener noreferrer">tautan</a>`},
+		{"tautan javascript", `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"li tanpa penutup", "<ul><li>satu<li>dua</ul>", "<ul><li>satu</li><li>dua</li></ul>"},
+		{"tag tidak ditutup", "<strong>tebal", "<strong>tebal</strong>"},
+		{"tag asing dibuang isinya dipertahankan", "<div><span>isi</span></div>", "isi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.in); got != tt.want {
+				t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"kurang dari", "<p>Nilai 5 < 6 dan seterusnya</p>", "Nilai 5 < 6 dan seterusnya"},
+		{"ampersand dan entitas", "A &amp; B & C&nbsp;D", "A & B & C D"},
+		{"atribut tanpa kutip", "<p>a<img src=x alt=gambar>b</p><p>c</p>", "ab\nc"},
+		{"daftar", "<ul><li>satu<li>dua</ul>", "- satu\n- dua"},
+		{"br", "a<br>b<br/>c", "a\nb\nc"},
+		{"spasi inline", "<p><b>tebal</b> biasa</p>", "tebal biasa"},
+		{"script", "a<script>var x = '<p>'</script>b", "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToText(tt.in); got != tt.want {
+				t.Errorf("HTMLToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"os"
 	"time"
 
@@ -9,11 +13,55 @@ import (
 
 var secretKey = []byte(os.Getenv("JWT_SECRET"))
 
-func GenerateToken(userID uint64, role string) (string, error) {
+const (
+	AccessTokenTTL        = 15 * time.Minute
+	RefreshTokenTTL       = 30 * 24 * time.Hour
+	TwoFactorChallengeTTL = 5 * time.Minute
+	PreviewTokenTTL       = time.Hour
+)
+
+// TokenRestrictions membatasi access token hanya untuk endpoint tertentu
+// sampai user menyelesaikan langkah wajib (ganti password, aktivasi 2FA).
+type TokenRestrictions struct {
+	MustChangePassword bool
+	TwoFactorSetup     bool
+}
+
+func GenerateToken(userID uint64, role string, sessionID uint64, restrictions TokenRestrictions) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"sid":     sessionID,
+		"jti":     GenerateRandomToken(16),
+		"iat":     now.Unix(),
+		"exp":     now.Add(AccessTokenTTL).Unix(),
+	}
+	if restrictions.MustChangePassword {
+		claims["pwd_change"] = true
+	}
+	if restrictions.TwoFactorSetup {
+		claims["2fa_setup"] = true
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secretKey)
+}
+
+// GeneratePreviewToken menerbitkan token pratinjau: pengajar dengan sesi sessionID melihat kelas
+// courseID sebagai mahasiswa sintetis dengan kategori aksesibilitas categories. Token ini
+// memakai role mahasiswa dan hanya bisa membaca (lihat AuthMiddleware).
+func GeneratePreviewToken(userID uint64, sessionID uint64, courseID uint64, categories []string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":         userID,
+		"role":            "student",
+		"sid":             sessionID,
+		"preview_course":  courseID,
+		"preview_profile": categories,
+		"jti":             GenerateRandomToken(16),
+		"iat":             now.Unix(),
+		"exp":             now.Add(PreviewTokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -28,3 +76,85 @@ func ValidateToken(tokenString string) (*jwt.Token, error) {
 		return secretKey, nil
 	})
 }
+
+// GenerateTwoFactorChallenge menerbitkan token singkat setelah password benar,
+// yang harus ditukar bersama kode 2FA di /auth/login/2fa. Token ini tidak memiliki
+// "sid" sehingga ditolak oleh AuthMiddleware.
+func GenerateTwoFactorChallenge(userID uint64) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"purpose": "2fa_challenge",
+		"jti":     GenerateRandomToken(16),
+		"iat":     now.Unix(),
+		"exp":     now.Add(TwoFactorChallengeTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secretKey)
+}
+
+func ParseTwoFactorChallenge(tokenString string) (uint64, error) {
+	token, err := ValidateToken(tokenString)
+	if err != nil || !token.Valid {
+		return 0, errors.New("token tidak valid")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != "2fa_challenge" {
+		return 0, errors.New("token tidak valid")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("token tidak valid")
+	}
+	return uint64(userID), nil
+}
+
+// GenerateCourseInviteToken menandatangani tautan undangan kelas. tokenID harus cocok dengan
+// CourseInvitation.TokenID agar tautan yang sudah diganti (undangan dikirim ulang) ditolak.
+func GenerateCourseInviteToken(invitationID uint64, tokenID string, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"inv":     invitationID,
+		"purpose": "course_invite",
+		"jti":     tokenID,
+		"iat":     time.Now().Unix(),
+		"exp":     expiresAt.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secretKey)
+}
+
+func ParseCourseInviteToken(tokenString string) (uint64, string, error) {
+	token, err := ValidateToken(tokenString)
+	if err != nil || !token.Valid {
+		return 0, "", errors.New("token undangan tidak valid atau sudah kadaluarsa")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != "course_invite" {
+		return 0, "", errors.New("token undangan tidak valid")
+	}
+
+	invitationID, ok := claims["inv"].(float64)
+	tokenID, _ := claims["jti"].(string)
+	if !ok || tokenID == "" {
+		return 0, "", errors.New("token undangan tidak valid")
+	}
+	return uint64(invitationID), tokenID, nil
+}
+
+// GenerateRandomToken menghasilkan string hex acak dari n byte crypto/rand.
+func GenerateRandomToken(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HashToken dipakai untuk menyimpan token opaque (refresh token, dsb.) tanpa plaintext di DB.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,298 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Batas ukuran file yang diunduh untuk dibaca metadatanya.
+const maxMediaProbeBytes = 200 << 20
+
+// MediaInfo adalah metadata file materi yang dibaca di server.
+type MediaInfo struct {
+	MimeType    string
+	FileSize    int64
+	DurationSec int // audio
+	Width       int // gambar
+	Height      int // gambar
+	PageCount   int // PDF (slide)
+}
+
+// ProbeMedia membaca jenis file (dari isi, bukan ekstensi), ukuran, durasi audio (WAV/MP3),
+// dimensi gambar (PNG/JPEG/GIF) dan jumlah halaman PDF dari path lokal atau URL.
+func ProbeMedia(pathOrURL string) (*MediaInfo, error) {
+	f, cleanup, err := openMediaFile(pathOrURL)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	info := &MediaInfo{
+		MimeType: http.DetectContentType(head[:n]),
+		FileSize: stat.Size(),
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(info.MimeType, "image/"):
+		cfg, _, err := image.DecodeConfig(f)
+		if err == nil {
+			info.Width, info.Height = cfg.Width, cfg.Height
+		}
+	case info.MimeType == "application/pdf":
+		r, err := pdf.NewReader(f, info.FileSize)
+		if err == nil {
+			info.PageCount = r.NumPage()
+		}
+	case info.MimeType == "audio/wave":
+		info.DurationSec = wavDuration(f)
+	case info.MimeType == "audio/mpeg" || isMP3Frame(head[:n]):
+		info.MimeType = "audio/mpeg"
+		info.DurationSec = mp3Duration(f, info.FileSize)
+	}
+
+	return info, nil
+}
+
+// Direktori upload lokal yang disajikan di "/storage/public". Hanya file di dalamnya yang boleh
+// dibaca dari disk.
+const localStorageRoot = "storage/public"
+
+// mediaHTTPClient dipakai untuk mengunduh file dari storage remote; tanpa timeout, server yang
+// lambat bisa menahan request selamanya.
+var mediaHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// LocalStoragePath mengubah path web atau URL upload lokal (".../storage/public/...") menjadi path
+// di disk. ok = false jika sumber bukan upload lokal atau keluar dari direktori storage.
+func LocalStoragePath(pathOrURL string) (string, bool) {
+	webPath := pathOrURL
+	if strings.HasPrefix(pathOrURL, "http://") || strings.HasPrefix(pathOrURL, "https://") {
+		u, err := url.Parse(pathOrURL)
+		if err != nil {
+			return "", false
+		}
+		webPath = u.Path
+	}
+
+	rel := filepath.Clean(strings.TrimPrefix(filepath.FromSlash(webPath), string(filepath.Separator)))
+	root := filepath.Clean(filepath.FromSlash(localStorageRoot))
+	if !strings.HasPrefix(rel, root+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// isAllowedMediaHost hanya menerima URL dari storage remote yang dikonfigurasi (SUPABASE_URL),
+// agar server tidak bisa dipakai mengunduh alamat sembarang (SSRF).
+func isAllowedMediaHost(u *url.URL) bool {
+	storageURL := os.Getenv("SUPABASE_URL")
+	if storageURL == "" {
+		return false
+	}
+	allowed, err := url.Parse(storageURL)
+	if err != nil || allowed.Host == "" {
+		return false
+	}
+	return (u.Scheme == "https" || u.Scheme == "http") && strings.EqualFold(u.Host, allowed.Host)
+}
+
+// openMediaFile membuka file upload lokal dari disk, atau mengunduh file dari storage remote ke
+// file sementara. Sumber lain ditolak.
+func openMediaFile(pathOrURL string) (*os.File, func(), error) {
+	// URL upload lokal dibaca langsung dari disk, tidak diunduh ulang lewat HTTP
+	if localPath, ok := LocalStoragePath(pathOrURL); ok {
+		f, err := os.Open(localPath)
+		if err != nil {
+			return nil, nil, errors.New("file tidak ditemukan")
+		}
+		stat, err := f.Stat()
+		if err != nil || !stat.Mode().IsRegular() {
+			f.Close()
+			return nil, nil, errors.New("file tidak ditemukan")
+		}
+		return f, func() { f.Close() }, nil
+	}
+
+	u, err := url.Parse(pathOrURL)
+	if err != nil || !isAllowedMediaHost(u) {
+		return nil, nil, errors.New("sumber file tidak diizinkan: gunakan file hasil upload")
+	}
+
+	resp, err := mediaHTTPClient.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.New("file tidak dapat diunduh: " + resp.Status)
+	}
+	if resp.ContentLength > maxMediaProbeBytes {
+		return nil, nil, errors.New("ukuran file melebihi batas 200MB")
+	}
+
+	tmpFile, err := os.CreateTemp("", "media-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}
+	written, err := io.Copy(tmpFile, io.LimitReader(resp.Body, maxMediaProbeBytes+1))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if written > maxMediaProbeBytes {
+		cleanup()
+		return nil, nil, errors.New("ukuran file melebihi batas 200MB")
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return tmpFile, cleanup, nil
+}
+
+// wavDuration membaca chunk "fmt " (byte rate) dan "data" (ukuran) dari file RIFF/WAVE.
+func wavDuration(r io.ReadSeeker) int {
+	if _, err := r.Seek(12, io.SeekStart); err != nil {
+		return 0
+	}
+
+	var byteRate uint32
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return 0
+		}
+		id := string(header[:4])
+		size := binary.LittleEndian.Uint32(header[4:])
+
+		switch id {
+		case "fmt ":
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(r, buf); err != nil || len(buf) < 12 {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(buf[8:12])
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return int(size / byteRate)
+		default:
+			if _, err := r.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+				return 0
+			}
+		}
+	}
+}
+
+var (
+	mp3Bitrates = map[bool][16]int{ // kbps, true = MPEG-1
+		true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	mp3SampleRates = map[int][3]int{ // versi: 3 = MPEG-1, 2 = MPEG-2, 0 = MPEG-2.5
+		3: {44100, 48000, 32000},
+		2: {22050, 24000, 16000},
+		0: {11025, 12000, 8000},
+	}
+)
+
+func isMP3Frame(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0xFF && b[1]&0xE0 == 0xE0
+}
+
+// mp3Duration menghitung durasi MPEG Layer III dari header Xing/Info (VBR) bila ada,
+// atau dari bitrate frame pertama (CBR).
+func mp3Duration(r io.ReadSeeker, fileSize int64) int {
+	data := make([]byte, 64<<10)
+	n, _ := io.ReadFull(r, data)
+	data = data[:n]
+
+	offset := 0
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		tagSize := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		offset = 10 + tagSize
+		if offset >= len(data) {
+			if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
+				return 0
+			}
+			n, _ = io.ReadFull(r, data[:cap(data)])
+			data = data[:n]
+			fileSize -= int64(offset)
+			offset = 0
+		}
+	}
+
+	for ; offset+4 <= len(data); offset++ {
+		if !isMP3Frame(data[offset:]) {
+			continue
+		}
+		header := binary.BigEndian.Uint32(data[offset:])
+		version := int(header>>19) & 0x3
+		layer := int(header>>17) & 0x3
+		bitrateIdx := int(header>>12) & 0xF
+		rateIdx := int(header>>10) & 0x3
+		if version == 1 || layer != 1 || bitrateIdx == 0 || bitrateIdx == 15 || rateIdx == 3 {
+			continue // bukan Layer III yang valid
+		}
+
+		mpeg1 := version == 3
+		sampleRate := mp3SampleRates[version][rateIdx]
+		bitrate := mp3Bitrates[mpeg1][bitrateIdx] * 1000
+		samplesPerFrame := 1152
+		if !mpeg1 {
+			samplesPerFrame = 576
+		}
+
+		if frames := xingFrameCount(data[offset:]); frames > 0 {
+			return int(int64(frames) * int64(samplesPerFrame) / int64(sampleRate))
+		}
+		return int((fileSize - int64(offset)) * 8 / int64(bitrate))
+	}
+	return 0
+}
+
+func xingFrameCount(frame []byte) uint32 {
+	limit := len(frame)
+	if limit > 64 {
+		limit = 64
+	}
+	for _, tag := range [][]byte{[]byte("Xing"), []byte("Info")} {
+		idx := bytes.Index(frame[:limit], tag)
+		if idx < 0 || idx+12 > len(frame) {
+			continue
+		}
+		flags := binary.BigEndian.Uint32(frame[idx+4:])
+		if flags&0x1 != 0 {
+			return binary.BigEndian.Uint32(frame[idx+8:])
+		}
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"/storage/public/a.mp3", "storage/public/a.mp3", true},
+		{"storage/public/imports/1/b.pdf", "storage/public/imports/1/b.pdf", true},
+		{"http://localhost:8080/storage/public/a%20b.png", "storage/public/a b.png", true},
+		{"https://api.example.com/storage/public/x/../y.pdf", "storage/public/y.pdf", true},
+		{"/storage/public/../../etc/passwd", "", false},
+		{"http://host/storage/public/%2e%2e/%2e%2e/etc/passwd", "", false},
+		{"/storage/public", "", false},
+		{"/storage/publicity/a.mp3", "", false},
+		{"/etc/passwd", "", false},
+		{"../storage/public/a.mp3", "", false},
+		{"http://169.254.169.254/latest/meta-data", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LocalStoragePath(tt.in)
+		if ok != tt.wantOK || got != filepath.FromSlash(tt.want) {
+			t.Errorf("LocalStoragePath(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIsAllowedMediaHost(t *testing.T) {
+	t.Setenv("SUPABASE_URL", "https://proj.supabase.co")
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"https://proj.supabase.co/storage/v1/object/public/uploads/a.mp3", true},
+		{"https://PROJ.supabase.co/a.mp3", true},
+		{"https://other.supabase.co/a.mp3", false},
+		{"https://proj.supabase.co.evil.com/a.mp3", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"file:///etc/passwd", false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.in)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", tt.in, err)
+		}
+		if got := isAllowedMediaHost(u); got != tt.want {
+			t.Errorf("isAllowedMediaHost(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	t.Setenv("SUPABASE_URL", "")
+	u, _ := url.Parse("https://proj.supabase.co/a.mp3")
+	if isAllowedMediaHost(u) {
+		t.Error("tanpa SUPABASE_URL semua host remote harus ditolak")
+	}
+}
+
+func TestProbeMediaRejectsArbitrarySources(t *testing.T) {
+	t.Setenv("SUPABASE_URL", "")
+	for _, src := range []string{"/etc/passwd", "http://127.0.0.1:1/x", "/storage/public/../../go.mod"} {
+		if _, err := ProbeMedia(src); err == nil {
+			t.Errorf("ProbeMedia(%q) seharusnya ditolak", src)
+		}
+	}
+}
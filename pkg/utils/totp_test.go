@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vektor uji RFC 6238 lampiran B (SHA1, secret ASCII "12345678901234567890"), dipotong ke 6 digit.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key, err := base32NoPadding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/TOTPPeriod); got != tt.want {
+			t.Errorf("totpCode(t=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / TOTPPeriod
+	key, _ := base32NoPadding.DecodeString(rfc6238Secret)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"step sekarang", rfc6238Secret, totpCode(key, current), current, true},
+		{"step sebelumnya", rfc6238Secret, totpCode(key, current-1), current - 1, true},
+		{"step berikutnya", rfc6238Secret, totpCode(key, current+1), current + 1, true},
+		{"di luar toleransi", rfc6238Secret, totpCode(key, current-2), 0, false},
+		{"spasi diabaikan", rfc6238Secret, " " + totpCode(key, current) + " ", current, true},
+		{"secret huruf kecil", strings.ToLower(rfc6238Secret), totpCode(key, current), current, true},
+		{"panjang salah", rfc6238Secret, "12345", 0, false},
+		{"secret rusak", "!!!", totpCode(key, current), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q) = %d, %v; want %d, %v", tt.code, step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	got := TOTPProvisioningURI("ABC", "a b@x.id", "Ramah Disabilitas")
+	want := "otpauth://totp/Ramah%20Disabilitas:a%20b@x.id?algorithm=SHA1&digits=6&issuer=Ramah+Disabilitas&period=30&secret=ABC"
+	if got != want {
+		t.Errorf("TOTPProvisioningURI = %q, want %q", got, want)
+	}
+}